@@ -2,45 +2,912 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"go.etcd.io/bbolt"
+
 	"github.com/arieffian/roman-alien-currency/internal/app"
 	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
 	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
 	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
-	contextDeadline = 10 * time.Second
+	readHeaderTimeout = 5 * time.Second
+	// shutdownTimeout bounds the wait for the requests in flight when the server stops.
+	shutdownTimeout = 5 * time.Second
+	// defaultTimeout bounds the commands registering -timeout, the other ones never time out.
+	defaultTimeout = 10 * time.Second
+	// defaultCommand runs when the first argument is not a command.
+	defaultCommand = "run"
 )
 
+type command struct {
+	name    string
+	args    string
+	summary string
+	// flags registers the flags of the command.
+	flags func(o *options, flags *flag.FlagSet)
+	run   func(ctx context.Context, o *options, env *environment, args []string) error
+}
+
+var commands = []command{
+	{
+		name:    "run",
+		args:    "[file ...]",
+		summary: "answer the questions of the files in order, use \"-\" to read from stdin (default \"input\")",
+		flags: func(o *options, flags *flag.FlagSet) {
+			o.registerLogging(flags)
+			o.registerKnowledge(flags)
+			o.registerStorage(flags)
+			o.registerAnswers(flags)
+			o.registerEvaluation(flags)
+			o.registerInputs(flags)
+			flags.StringVar(&o.output, "output", o.output, "file the answers are written to, stdout when empty")
+			flags.BoolVar(&o.diagnostics, "diagnostics", o.diagnostics, "print the position of every unanswerable question and corrected typo to stderr")
+			flags.BoolVar(&o.strict, "strict", o.strict, "exit with an error status when a definition cannot be learned or a question cannot be answered")
+		},
+		run: runCli,
+	},
+	{
+		name:    "repl",
+		summary: "type statements and questions line by line",
+		flags: func(o *options, flags *flag.FlagSet) {
+			o.registerLogging(flags)
+			o.registerKnowledge(flags)
+			o.registerStorage(flags)
+			o.registerAnswers(flags)
+		},
+		run: runRepl,
+	},
+	{
+		name:    "serve",
+		summary: "expose the guide as a JSON API",
+		flags: func(o *options, flags *flag.FlagSet) {
+			o.registerLogging(flags)
+			o.registerKnowledge(flags)
+			o.registerAnswers(flags)
+			flags.StringVar(&o.addr, "addr", o.addr, "address the http server listens on")
+		},
+		run: runServer,
+	},
+	{
+		name:    "verify",
+		args:    "dir",
+		summary: "check that every name.in file of dir prints name.out",
+		flags: func(o *options, flags *flag.FlagSet) {
+			o.registerLogging(flags)
+			o.registerKnowledge(flags)
+			o.registerAnswers(flags)
+			o.registerEvaluation(flags)
+		},
+		run: runVerify,
+	},
+	{
+		name:    "lint",
+		args:    "[file ...]",
+		summary: "report the typos, rejected definitions, conflicts and unanswerable questions of the files",
+		flags: func(o *options, flags *flag.FlagSet) {
+			o.registerLogging(flags)
+			o.registerKnowledge(flags)
+			o.registerStorage(flags)
+			o.registerTimeout(flags)
+			o.registerInputs(flags)
+		},
+		run: runLint,
+	},
+	{
+		name:    "prices",
+		args:    "commodity [file ...]",
+		summary: "print every price of a commodity learned from the files, along with its changes in percent",
+		flags: func(o *options, flags *flag.FlagSet) {
+			o.registerLogging(flags)
+			o.registerKnowledge(flags)
+			o.registerStorage(flags)
+			o.registerAnswers(flags)
+			o.registerTimeout(flags)
+			o.registerInputs(flags)
+			flags.BoolVar(&o.diagnostics, "diagnostics", o.diagnostics, "print the position of every definition that cannot be learned to stderr")
+			flags.BoolVar(&o.strict, "strict", o.strict, "exit with an error status when a definition cannot be learned")
+		},
+		run: runPrices,
+	},
+	{
+		name:    "export",
+		args:    "file",
+		summary: "write the knowledge base to a JSON file, \"-\" for stdout",
+		flags: func(o *options, flags *flag.FlagSet) {
+			o.registerLogging(flags)
+			o.registerKnowledge(flags)
+			o.registerStorage(flags)
+		},
+		run: runExport,
+	},
+	{
+		name:    "import",
+		args:    "file",
+		summary: "replace the stored knowledge base by a JSON file",
+		flags: func(o *options, flags *flag.FlagSet) {
+			o.registerLogging(flags)
+			o.registerKnowledge(flags)
+			o.registerStorage(flags)
+		},
+		run: runImport,
+	},
+}
+
 func main() {
-	log.SetFormatter(&log.JSONFormatter{})
+	os.Exit(execute(os.Args[1:]))
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), contextDeadline)
-	defer cancel()
+// execute runs the command named by the first argument, the default command
+// when it names no command, and returns the exit code.
+func execute(args []string) int {
+	if len(args) > 0 && args[0] == "help" {
+		return help(args[1:])
+	}
 
-	converter := converters.NewConverter()
-	parser := parsers.NewParser(parsers.NewParserParams{
-		Converter:       converter,
-		AlienDictionary: map[string]string{},
-		MetalValue:      map[string]float64{},
-	})
-	fileReader := readers.NewFile()
+	cmd, _ := lookup(defaultCommand)
+	if len(args) > 0 {
+		if named, ok := lookup(args[0]); ok {
+			cmd = named
+			args = args[1:]
+		}
+	}
+
+	o := defaultOptions()
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.Usage = func() {
+		commandUsage(flags, cmd)
+	}
+	cmd.flags(o, flags)
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if err := configureLogging(o); err != nil {
+		return report(err)
+	}
+
+	env, err := newEnvironment(o)
+	if err != nil {
+		return report(err)
+	}
+	defer env.close()
+
+	// the first interrupt stops the command gracefully, the next one kills it
+	sigCtx, stop := notifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx := sigCtx
+	if o.timeout > 0 {
+		timeoutCtx, cancel := context.WithTimeout(sigCtx, o.timeout)
+		defer cancel()
+		ctx = timeoutCtx
+	}
+
+	err = cmd.run(ctx, o, env, flags.Args())
+
+	// the exit code tells which signal stopped the command
+	var stopped *interruption
+	if errors.Is(err, context.Canceled) && errors.As(context.Cause(sigCtx), &stopped) {
+		err = &interruption{signal: stopped.signal, err: err}
+	}
+
+	return report(err)
+}
+
+// interruption is a command stopped by signal, err is the error the command
+// returned, it is nil for the cause of the canceled context.
+type interruption struct {
+	signal os.Signal
+	err    error
+}
+
+func (i *interruption) Error() string {
+	if i.err == nil {
+		return fmt.Sprintf("stopped by %s", i.signal)
+	}
+
+	return i.err.Error()
+}
+
+func (i *interruption) Unwrap() error {
+	return i.err
+}
+
+// notifyContext is signal.NotifyContext recording the signal that canceled
+// the context as its cause. Only the first signal is caught, the next ones
+// are handled by default.
+func notifyContext(parent context.Context, signals ...os.Signal) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	go func() {
+		select {
+		case sig := <-received:
+			cancel(&interruption{signal: sig})
+		case <-ctx.Done():
+		}
+		signal.Stop(received)
+	}()
+
+	return ctx, func() {
+		cancel(nil)
+	}
+}
+
+// report logs err and returns its exit code.
+func report(err error) int {
+	if err != nil {
+		log.Error(err)
+	}
+
+	return exitCode(err)
+}
+
+func lookup(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+
+	return command{}, false
+}
+
+// help prints the usage of the command named by args, or the list of commands and the exit codes.
+func help(args []string) int {
+	if len(args) > 0 {
+		cmd, ok := lookup(args[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command '%s'\n", args[0])
+			return exitUsage
+		}
+
+		flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		cmd.flags(defaultOptions(), flags)
+		commandUsage(flags, cmd)
+
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "usage: %s [command] [flags] [args]\n\ncommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\n%s is the default command, \"%s help command\" lists the flags of a command\n", defaultCommand, os.Args[0])
+	fmt.Fprintf(os.Stderr, `
+exit codes:
+  %d  success
+  %d  any other error
+  %d  invalid command, flag or flag value
+  %d  file that cannot be read or written
+  %d  definition, knowledge base or messages that cannot be learned, or a problem found by lint
+  %d  unanswerable question, with -strict
+  %d  failed golden case
+  %d  stopped by -timeout
+  %d  stopped by an interrupt
+  %d  stopped by SIGTERM
+`, exitOK, exitFailure, exitUsage, exitIO, exitParse, exitUnanswered, exitMismatch, exitTimeout, exitInterrupted, exitTerminated)
+
+	return exitOK
+}
+
+func commandUsage(flags *flag.FlagSet, cmd command) {
+	usage := strings.TrimSpace(fmt.Sprintf("%s %s [flags] %s", os.Args[0], cmd.name, cmd.args))
+	fmt.Fprintf(flags.Output(), "usage: %s\n\n%s\n\nflags:\n", usage, cmd.summary)
+	flags.PrintDefaults()
+}
+
+func runCli(ctx context.Context, o *options, env *environment, args []string) error {
+	output, err := openOutput(o.output)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
 
 	cli, err := app.NewCli(app.NewCliParams{
-		Converter:  converter,
-		Parser:     parser,
-		FileReader: fileReader,
+		Converter:   env.converter,
+		Parser:      env.parser,
+		FileReader:  env.fileReader,
+		Renderer:    env.renderer,
+		Storage:     env.storage,
+		Inputs:      append(o.inputs, args...),
+		Output:      output,
+		Diagnostics: diagnosticsOutput(o.diagnostics),
+		Hoist:       o.hoist,
+		Format:      env.format,
+		Rounding:    env.rounding,
+		Strict:      o.strict,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create the new cli: %w", err)
+	}
+
+	return cli.Run(ctx)
+}
+
+func runRepl(ctx context.Context, o *options, env *environment, args []string) error {
+	repl, err := app.NewRepl(app.NewReplParams{
+		Parser:     env.parser,
+		FileReader: env.fileReader,
+		Renderer:   env.renderer,
+		Storage:    env.storage,
+		Rounding:   env.rounding,
+		Input:      os.Stdin,
+		Output:     os.Stdout,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create the new repl: %w", err)
+	}
+
+	return repl.Run(ctx)
+}
+
+func runServer(ctx context.Context, o *options, env *environment, args []string) error {
+	server, err := app.NewServer(app.NewServerParams{
+		Converter: env.converter,
+		Renderer:  env.renderer,
+		NewParser: env.newParser,
+		Rounding:  env.rounding,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create the new server: %w", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              o.addr,
+		Handler:           server,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		shutdown <- httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Infof("listening on %s", o.addr)
+
+	err = httpServer.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	log.Info("shutting down")
+
+	return <-shutdown
+}
+
+// runVerify fails with app.ErrMismatch when any golden case of the directory fails.
+func runVerify(ctx context.Context, o *options, env *environment, args []string) error {
+	if len(args) != 1 {
+		return usageErr(errors.New("verify requires a directory"))
+	}
+
+	verifier, err := app.NewVerifier(app.NewVerifierParams{
+		Converter:  env.converter,
+		NewParser:  env.newParser,
+		FileReader: env.fileReader,
+		Renderer:   env.renderer,
+		Dir:        args[0],
+		Output:     os.Stdout,
+		Hoist:      o.hoist,
+		Format:     env.format,
+		Rounding:   env.rounding,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create the new verifier: %w", err)
+	}
+
+	return verifier.Run(ctx)
+}
+
+// runLint fails with app.ErrLintFailed when any problem is found.
+func runLint(ctx context.Context, o *options, env *environment, args []string) error {
+	linter, err := app.NewLinter(app.NewLinterParams{
+		Parser:     env.parser,
+		FileReader: env.fileReader,
+		Inputs:     append(o.inputs, args...),
+		Output:     os.Stdout,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create the new linter: %w", err)
+	}
+
+	return linter.Run(ctx)
+}
+
+// runPrices prints the price history of the commodity named by the first argument.
+func runPrices(ctx context.Context, o *options, env *environment, args []string) error {
+	if len(args) == 0 {
+		return usageErr(errors.New("prices requires a commodity"))
+	}
+
+	history, err := app.NewPriceHistory(app.NewPriceHistoryParams{
+		Parser:      env.parser,
+		FileReader:  env.fileReader,
+		Inputs:      append(o.inputs, args[1:]...),
+		Commodity:   args[0],
+		Output:      os.Stdout,
+		Diagnostics: diagnosticsOutput(o.diagnostics),
+		Rounding:    env.rounding,
+		Strict:      o.strict,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to create the new price history: %w", err)
+	}
+
+	return history.Run(ctx)
+}
+
+// runExport writes the knowledge base to a file, or to the standard output for "-".
+func runExport(ctx context.Context, o *options, env *environment, args []string) error {
+	if len(args) != 1 {
+		return usageErr(errors.New("export requires a file"))
+	}
+
+	output, err := openOutput(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to create the export: %w", err)
+	}
+	defer output.Close()
+
+	err = storages.Encode(output, env.parser.Knowledge())
+	if err != nil {
+		return fmt.Errorf("failed to export the knowledge base: %w", err)
+	}
+
+	return nil
+}
+
+// runImport replaces the stored knowledge base by the one of a file.
+func runImport(ctx context.Context, o *options, env *environment, args []string) error {
+	if len(args) != 1 {
+		return usageErr(errors.New("import requires a file"))
+	}
+	if env.storage == nil {
+		return usageErr(errors.New("import needs a storage, set -storage"))
+	}
+
+	file, err := env.fileReader.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open the import: %w", err)
+	}
+	defer file.Close()
+
+	knowledge, err := storages.Decode(file)
+	if err != nil {
+		return fmt.Errorf("failed to read the import: %w", err)
+	}
+
+	err = env.parser.Restore(knowledge)
+	if err != nil {
+		return fmt.Errorf("failed to import the knowledge base: %w", err)
+	}
+
+	err = env.storage.Save(env.parser.Knowledge())
+	if err != nil {
+		return fmt.Errorf("failed to save the knowledge base: %w", err)
+	}
+
+	return nil
+}
+
+// options are the flags of the commands, every command only registers the flags it uses.
+type options struct {
+	logLevel  string
+	logFormat string
+
+	allowCommodities     string
+	denyCommodities      string
+	romanNotation        string
+	numeralSystem        string
+	strictTypos          bool
+	correctionConfidence float64
+	conflictPolicy       string
+	storageKind          string
+	storagePath          string
+
+	messagesFile string
+	roundingMode string
+	precision    int
+	alienAmounts bool
+	format       string
+
+	hoist       bool
+	timeout     time.Duration
+	inputs      stringList
+	output      string
+	diagnostics bool
+	strict      bool
+	addr        string
+}
+
+func defaultOptions() *options {
+	return &options{
+		logLevel:             log.InfoLevel.String(),
+		logFormat:            "text",
+		romanNotation:        string(converters.NotationClassic),
+		numeralSystem:        converters.RomanSystem,
+		correctionConfidence: 0.7,
+		conflictPolicy:       string(parsers.ConflictWarn),
+		roundingMode:         string(rationals.RoundHalfUp),
+		precision:            rationals.DefaultRounding.Precision,
+		format:               string(renderers.FormatText),
+		diagnostics:          true,
+		addr:                 ":8080",
+	}
+}
+
+func (o *options) registerLogging(flags *flag.FlagSet) {
+	flags.StringVar(&o.logLevel, "log-level", o.logLevel, "lowest level of the logs: debug, info, warn or error")
+	flags.StringVar(&o.logFormat, "log-format", o.logFormat, "format of the logs: text or json")
+}
+
+func (o *options) registerKnowledge(flags *flag.FlagSet) {
+	flags.StringVar(&o.allowCommodities, "allow-commodities", o.allowCommodities, "comma separated commodities that can be learned, any commodity when empty")
+	flags.StringVar(&o.denyCommodities, "deny-commodities", o.denyCommodities, "comma separated commodities that can never be learned")
+	flags.StringVar(&o.romanNotation, "roman-notation", o.romanNotation, "notation of the roman numbers from 4000 and up: classic, vinculum or apostrophus")
+	flags.StringVar(&o.numeralSystem, "numeral-system", o.numeralSystem, "numeral system of the alien words until a dictionary declares another one: roman, attic, babylonian or mayan")
+	flags.BoolVar(&o.strictTypos, "strict-typos", o.strictTypos, "report unknown words instead of correcting them")
+	flags.Float64Var(&o.correctionConfidence, "correction-confidence", o.correctionConfidence, "confidence, between 0 and 1, a typo correction needs to be applied")
+	flags.StringVar(&o.conflictPolicy, "conflict-policy", o.conflictPolicy, "what to do with a definition contradicting an earlier one: error, warn, last_wins or first_wins")
+}
+
+// registerStorage is not used by serve and verify, every request and golden
+// case starts without any knowledge.
+func (o *options) registerStorage(flags *flag.FlagSet) {
+	flags.StringVar(&o.storageKind, "storage", o.storageKind, "keep the learned alien words and prices between runs: json or bolt, nothing is kept when empty")
+	flags.StringVar(&o.storagePath, "storage-path", o.storagePath, "file of the storage (default \"knowledge.json\" or \"knowledge.db\")")
+}
+
+func (o *options) registerAnswers(flags *flag.FlagSet) {
+	flags.StringVar(&o.messagesFile, "messages", o.messagesFile, "JSON file mapping error categories to the messages shown to the user")
+	flags.StringVar(&o.roundingMode, "rounding", o.roundingMode, "rounding of the credits: half_up, half_even, down, up or exact")
+	flags.IntVar(&o.precision, "precision", o.precision, "largest number of decimals of the credits")
+	flags.BoolVar(&o.alienAmounts, "alien-amounts", o.alienAmounts, "write the whole amounts of an exchange with alien words")
+}
+
+func (o *options) registerEvaluation(flags *flag.FlagSet) {
+	flags.StringVar(&o.format, "format", o.format, "format of the answers: text, json, ndjson or csv")
+	flags.BoolVar(&o.hoist, "hoist-definitions", o.hoist, "learn every definition of a file before answering its questions, instead of evaluating its lines in order")
+	o.registerTimeout(flags)
+}
+
+func (o *options) registerTimeout(flags *flag.FlagSet) {
+	flags.DurationVar(&o.timeout, "timeout", defaultTimeout, "longest duration of the command, 0 never times out")
+}
+
+func (o *options) registerInputs(flags *flag.FlagSet) {
+	flags.Var(&o.inputs, "input", "file processed before the arguments, can be repeated")
+}
+
+// environment holds the services shared by the commands, built from the options.
+type environment struct {
+	converter  converters.ConverterService
+	newParser  app.ParserFactory
+	parser     parsers.ParserService
+	fileReader readers.FileService
+	renderer   renderers.RendererService
+	storage    storages.StorageService
+	rounding   rationals.Rounding
+	format     renderers.Format
+}
+
+// newEnvironment validates the options and restores the stored knowledge base, if any.
+func newEnvironment(o *options) (*environment, error) {
+	notation, err := converters.ParseNotation(o.romanNotation)
+	if err != nil {
+		return nil, usageErr(err)
+	}
+
+	mode, err := rationals.ParseRoundingMode(o.roundingMode)
+	if err != nil {
+		return nil, usageErr(err)
+	}
+
+	policy, err := parsers.ParseConflictPolicy(o.conflictPolicy)
+	if err != nil {
+		return nil, usageErr(err)
+	}
+
+	format, err := renderers.ParseFormat(o.format)
+	if err != nil {
+		return nil, usageErr(err)
+	}
+
+	converter := converters.NewConverter(converters.NewConverterParams{
+		Notation: notation,
+	})
+	numerals := converters.NewNumeralRegistry(converters.NewNumeralRegistryParams{
+		Converter: converter,
 	})
+	system, err := numerals.Get(o.numeralSystem)
+	if err != nil {
+		return nil, usageErr(err)
+	}
+
+	rounding := rationals.Rounding{Mode: mode, Precision: o.precision}
+
+	newParser := func(converter converters.ConverterService) parsers.ParserService {
+		return parsers.NewParser(parsers.NewParserParams{
+			Converter:               converter,
+			AlienDictionary:         map[string]string{},
+			MetalValue:              map[string]rationals.Rational{},
+			CommodityAllowList:      splitList(o.allowCommodities),
+			CommodityDenyList:       splitList(o.denyCommodities),
+			Numerals:                numerals,
+			NumeralSystem:           system,
+			StrictTypos:             o.strictTypos,
+			MinCorrectionConfidence: o.correctionConfidence,
+			ConflictPolicy:          policy,
+			Rounding:                rounding,
+		})
+	}
+	parser := newParser(converter)
+	fileReader := readers.NewFile()
+
+	messages, err := loadMessages(fileReader, o.messagesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the messages: %w", err)
+	}
+
+	env := &environment{
+		converter:  converter,
+		newParser:  newParser,
+		parser:     parser,
+		fileReader: fileReader,
+		renderer: renderers.NewText(renderers.NewTextParams{
+			Messages:      messages,
+			Rounding:      rounding,
+			AlienNumerals: o.alienAmounts,
+		}),
+		rounding: rounding,
+		format:   format,
+	}
+
+	env.storage, err = openStorage(o.storageKind, o.storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the storage: %w", err)
+	}
+	if env.storage == nil {
+		return env, nil
+	}
+
+	knowledge, err := env.storage.Load()
+	if err != nil {
+		env.close()
+		return nil, fmt.Errorf("failed to load the knowledge base: %w", err)
+	}
+
+	err = parser.Restore(knowledge)
+	if err != nil {
+		env.close()
+		return nil, fmt.Errorf("failed to restore the knowledge base: %w", err)
+	}
+
+	return env, nil
+}
+
+func (e *environment) close() {
+	if e.storage != nil {
+		e.storage.Close()
+	}
+}
 
+// configureLogging applies the log level and format.
+func configureLogging(o *options) error {
+	level, err := log.ParseLevel(o.logLevel)
 	if err != nil {
-		log.Fatalf("failed to create the new cli: %s\n", err)
+		return usageErr(err)
 	}
+	log.SetLevel(level)
 
-	err = cli.Run(ctx)
+	switch o.logFormat {
+	case "text":
+		log.SetFormatter(&log.TextFormatter{DisableTimestamp: true})
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return usageErr(fmt.Errorf("unknown log format '%s'", o.logFormat))
+	}
+
+	return nil
+}
+
+// openStorage opens the storage of kind at path, there is no storage when kind is empty.
+func openStorage(kind string, path string) (storages.StorageService, error) {
+	switch kind {
+	case "":
+		return nil, nil
+	case "json":
+		if path == "" {
+			path = "knowledge.json"
+		}
+
+		return storages.NewJSONFile(storages.NewJSONFileParams{Path: path}), nil
+	case "bolt":
+		if path == "" {
+			path = "knowledge.db"
+		}
+
+		return storages.NewBolt(storages.NewBoltParams{Path: path})
+	default:
+		return nil, usageErr(fmt.Errorf("unknown storage '%s'", kind))
+	}
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+
+	return strings.Split(list, ",")
+}
+
+func loadMessages(fileReader readers.FileService, fileLoc string) (renderers.MessageCatalog, error) {
+	if fileLoc == "" {
+		return nil, nil
+	}
+
+	file, err := fileReader.Open(fileLoc)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	defer file.Close()
+
+	return renderers.LoadMessageCatalog(file)
+}
+
+// openOutput opens fileLoc for writing, the standard output when it is empty or readers.Stdin.
+func openOutput(fileLoc string) (io.WriteCloser, error) {
+	if fileLoc == "" || fileLoc == readers.Stdin {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+
+	return os.Create(fileLoc)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func diagnosticsOutput(enabled bool) io.Writer {
+	if !enabled {
+		return nil
+	}
+
+	return os.Stderr
+}
+
+// stringList is a flag that can be repeated.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// Exit codes of the commands, documented in the README.
+const (
+	exitOK = 0
+	// exitFailure is any error without a code of its own.
+	exitFailure = 1
+	// exitUsage is an unknown command, flag or flag value.
+	exitUsage = 2
+	// exitIO is a file that cannot be read or written.
+	exitIO = 3
+	// exitParse is a definition, knowledge base or catalog that cannot be
+	// learned, or a problem reported by lint.
+	exitParse = 4
+	// exitUnanswered is a question that cannot be answered, with -strict.
+	exitUnanswered = 5
+	// exitMismatch is a golden case that failed.
+	exitMismatch = 6
+	// exitTimeout is a command stopped by -timeout.
+	exitTimeout = 7
+	// exitInterrupted is a command stopped by an interrupt, like a shell reports SIGINT.
+	exitInterrupted = 130
+	// exitTerminated is a command stopped by SIGTERM, like a shell reports it.
+	exitTerminated = 143
+)
+
+// usageError is an invalid flag value.
+type usageError struct {
+	err error
+}
+
+func usageErr(err error) error {
+	return &usageError{err: err}
+}
+
+func (u *usageError) Error() string {
+	return u.err.Error()
+}
+
+func (u *usageError) Unwrap() error {
+	return u.err
+}
+
+// exitCode returns the exit code reporting err.
+func exitCode(err error) int {
+	var (
+		usage        *usageError
+		diagnostic   *parsers.Diagnostic
+		syntaxErr    *json.SyntaxError
+		unmarshalErr *json.UnmarshalTypeError
+		pathErr      *fs.PathError
+		stopped      *interruption
+	)
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	case errors.As(err, &stopped) && stopped.signal == syscall.SIGTERM:
+		return exitTerminated
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	case errors.Is(err, app.ErrUnanswered):
+		return exitUnanswered
+	case errors.Is(err, app.ErrMismatch):
+		return exitMismatch
+	case errors.Is(err, app.ErrLintFailed),
+		errors.As(err, &diagnostic),
+		errors.Is(err, storages.ErrInvalidKnowledgeBase),
+		errors.Is(err, converters.ErrUnknownNumeralSystem),
+		errors.As(err, &syntaxErr),
+		errors.As(err, &unmarshalErr):
+		return exitParse
+	case errors.As(err, &pathErr),
+		errors.Is(err, app.ErrNoGoldenCase),
+		errors.Is(err, bbolt.ErrTimeout):
+		return exitIO
+	default:
+		return exitFailure
 	}
 }
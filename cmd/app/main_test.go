@@ -0,0 +1,426 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/arieffian/roman-alien-currency/internal/app"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
+	"github.com/go-test/deep"
+)
+
+func TestExitCode(t *testing.T) {
+
+	type args struct {
+		err error
+	}
+
+	type want struct {
+		code int
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name:       "when there is no error should exit with success",
+			args:       args{err: nil},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitOK},
+		},
+		{
+			name:       "when error has no code of its own should exit with failure",
+			args:       args{err: errors.New("failure")},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitFailure},
+		},
+		{
+			name:       "when flag value is invalid should exit with usage",
+			args:       args{err: usageErr(errors.New("invalid flag"))},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitUsage},
+		},
+		{
+			name:       "when file cannot be read should exit with io",
+			args:       args{err: fmt.Errorf("failed to open: %w", &fs.PathError{Op: "open", Path: "input", Err: fs.ErrNotExist})},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitIO},
+		},
+		{
+			name:       "when golden case is missing should exit with io",
+			args:       args{err: app.ErrNoGoldenCase},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitIO},
+		},
+		{
+			name:       "when definition cannot be learned should exit with parse",
+			args:       args{err: &parsers.Diagnostic{Line: 1, Column: 1, Err: errors.New("invalid number '-5'")}},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitParse},
+		},
+		{
+			name:       "when knowledge base is invalid should exit with parse",
+			args:       args{err: fmt.Errorf("failed to import: %w", storages.ErrInvalidKnowledgeBase)},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitParse},
+		},
+		{
+			name:       "when numeral system is unknown should exit with parse",
+			args:       args{err: fmt.Errorf("%w 'klingon'", converters.ErrUnknownNumeralSystem)},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitParse},
+		},
+		{
+			name:       "when lint fails should exit with parse",
+			args:       args{err: app.ErrLintFailed},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitParse},
+		},
+		{
+			name:       "when question cannot be answered should exit with unanswered",
+			args:       args{err: app.ErrUnanswered},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitUnanswered},
+		},
+		{
+			name:       "when golden case fails should exit with mismatch",
+			args:       args{err: app.ErrMismatch},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitMismatch},
+		},
+		{
+			name:       "when timeout is exceeded should exit with timeout",
+			args:       args{err: fmt.Errorf("%w, 3 lines processed", context.DeadlineExceeded)},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitTimeout},
+		},
+		{
+			name:       "when context is canceled should exit with interrupted",
+			args:       args{err: fmt.Errorf("%w, 3 lines processed", context.Canceled)},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitInterrupted},
+		},
+		{
+			name:       "when interrupt stops the command should exit with interrupted",
+			args:       args{err: &interruption{signal: os.Interrupt, err: context.Canceled}},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitInterrupted},
+		},
+		{
+			name:       "when sigterm stops the command should exit with terminated",
+			args:       args{err: &interruption{signal: syscall.SIGTERM, err: context.Canceled}},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitTerminated},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result := exitCode(tc.args.err)
+
+			if diff := deep.Equal(result, tc.want.code); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.code, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestExecute(t *testing.T) {
+
+	type args struct {
+		args []string
+	}
+
+	type want struct {
+		code int
+	}
+
+	// writeFile writes content to name in dir and returns its path.
+	writeFile := func(t *testing.T, dir string, name string, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("got unexpected error: %v", err)
+		}
+
+		return path
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when questions are answered should exit with success",
+			args: args{},
+			beforeEach: func(t *testing.T, a *args) {
+				dir := t.TempDir()
+				input := writeFile(t, dir, "input", "glob is I\nhow much is glob ?\n")
+				a.args = []string{"-output", filepath.Join(dir, "output"), input}
+			},
+			want: want{code: exitOK},
+		},
+		{
+			name: "when server cannot listen should exit with failure",
+			args: args{
+				args: []string{"serve", "-addr", ":-1"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitFailure},
+		},
+		{
+			name: "when flag is unknown should exit with usage",
+			args: args{
+				args: []string{"-unknown"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitUsage},
+		},
+		{
+			name: "when flag value is invalid should exit with usage",
+			args: args{
+				args: []string{"-rounding", "sideways"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitUsage},
+		},
+		{
+			name: "when input cannot be read should exit with io",
+			args: args{},
+			beforeEach: func(t *testing.T, a *args) {
+				dir := t.TempDir()
+				a.args = []string{"-output", filepath.Join(dir, "output"), filepath.Join(dir, "missing")}
+			},
+			want: want{code: exitIO},
+		},
+		{
+			name: "when definition cannot be learned in strict mode should exit with parse",
+			args: args{},
+			beforeEach: func(t *testing.T, a *args) {
+				dir := t.TempDir()
+				input := writeFile(t, dir, "input", "glob is I\nglob Gold is -5 Credits\n")
+				a.args = []string{"-strict", "-output", filepath.Join(dir, "output"), input}
+			},
+			want: want{code: exitParse},
+		},
+		{
+			name: "when question cannot be answered in strict mode should exit with unanswered",
+			args: args{},
+			beforeEach: func(t *testing.T, a *args) {
+				dir := t.TempDir()
+				input := writeFile(t, dir, "input", "how much is glob ?\n")
+				a.args = []string{"-strict", "-output", filepath.Join(dir, "output"), input}
+			},
+			want: want{code: exitUnanswered},
+		},
+		{
+			name: "when golden case fails should exit with mismatch",
+			args: args{},
+			beforeEach: func(t *testing.T, a *args) {
+				dir := t.TempDir()
+				writeFile(t, dir, "case.in", "glob is I\nhow much is glob ?\n")
+				writeFile(t, dir, "case.out", "glob is 2\n")
+				a.args = []string{"verify", dir}
+			},
+			want: want{code: exitMismatch},
+		},
+		{
+			name: "when timeout is exceeded should exit with timeout",
+			args: args{},
+			beforeEach: func(t *testing.T, a *args) {
+				dir := t.TempDir()
+				input := writeFile(t, dir, "input", "glob is I\nhow much is glob ?\n")
+				a.args = []string{"-timeout", "1ns", "-output", filepath.Join(dir, "output"), input}
+			},
+			want: want{code: exitTimeout},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result := execute(tc.args.args)
+
+			if diff := deep.Equal(result, tc.want.code); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.code, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestExecuteSignals(t *testing.T) {
+
+	// the signals sent to the test never kill it, even before execute catches them
+	caught := make(chan os.Signal, 1)
+	signal.Notify(caught, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(caught)
+
+	type args struct {
+		signal syscall.Signal
+	}
+
+	type want struct {
+		code int
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name:       "when interrupt stops the repl should exit with interrupted",
+			args:       args{signal: syscall.SIGINT},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitInterrupted},
+		},
+		{
+			name:       "when sigterm stops the repl should exit with terminated",
+			args:       args{signal: syscall.SIGTERM},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{code: exitTerminated},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			// the repl waits for a line that never comes
+			stdin, writer, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			defer writer.Close()
+
+			original := os.Stdin
+			os.Stdin = stdin
+			defer func() {
+				os.Stdin = original
+			}()
+
+			done := make(chan int)
+			go func() {
+				done <- execute([]string{"repl"})
+			}()
+
+			// the signal is sent until execute catches it
+			var result int
+			ticker := time.NewTicker(50 * time.Millisecond)
+			defer ticker.Stop()
+		wait:
+			for {
+				select {
+				case result = <-done:
+					break wait
+				case <-ticker.C:
+					if err := syscall.Kill(os.Getpid(), tc.args.signal); err != nil {
+						t.Fatalf("got unexpected error: %v", err)
+					}
+				}
+			}
+
+			if diff := deep.Equal(result, tc.want.code); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.code, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestNotifyContext(t *testing.T) {
+
+	caught := make(chan os.Signal, 1)
+	signal.Notify(caught, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(caught)
+
+	type args struct {
+		signal syscall.Signal
+	}
+
+	type want struct {
+		signal os.Signal
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name:       "when interrupt is received should cancel with the interrupt as cause",
+			args:       args{signal: syscall.SIGINT},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{signal: os.Interrupt},
+		},
+		{
+			name:       "when sigterm is received should cancel with sigterm as cause",
+			args:       args{signal: syscall.SIGTERM},
+			beforeEach: func(t *testing.T, a *args) {},
+			want:       want{signal: syscall.SIGTERM},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			ctx, stop := notifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := syscall.Kill(os.Getpid(), tc.args.signal); err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+				t.Fatalf("got no cancellation after %s", tc.args.signal)
+			}
+
+			var stopped *interruption
+			if !errors.As(context.Cause(ctx), &stopped) {
+				t.Fatalf("got unexpected cause.\n expected: %v\n actual: %v\n", tc.want.signal, context.Cause(ctx))
+			}
+
+			if diff := deep.Equal(stopped.signal, tc.want.signal); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.signal, stopped.signal, diff)
+			}
+		})
+
+	}
+
+	t.Run("when stop is called should cancel without signal", func(t *testing.T) {
+		ctx, stop := notifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		stop()
+
+		if diff := deep.Equal(context.Cause(ctx), context.Canceled); diff != nil {
+			t.Errorf("got unexpected cause.\n expected: %v\n actual: %v\n diff: %v\n", context.Canceled, context.Cause(ctx), diff)
+		}
+	})
+}
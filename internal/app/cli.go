@@ -2,88 +2,414 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"io"
+	"os"
 
 	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
 	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
 	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
 )
 
+// defaultInput is read when no input is given to the cli.
+const defaultInput = "input"
+
+var ErrUnanswered = errors.New("unanswerable questions")
+
 type cli struct {
-	converter  converters.ConverterService
-	parser     parsers.ParserService
-	fileReader readers.FileService
+	converter   converters.ConverterService
+	parser      parsers.ParserService
+	fileReader  readers.FileService
+	renderer    renderers.RendererService
+	writer      renderers.AnswerWriter
+	storage     storages.StorageService
+	inputs      []string
+	output      io.Writer
+	diagnostics io.Writer
+	hoist       bool
+	strict      bool
+	// reportedConflicts is the number of conflicts already reported.
+	reportedConflicts int
+	// questions and unanswered count the questions written so far.
+	questions  int
+	unanswered int
+	// processed counts the lines evaluated so far.
+	processed int
 }
 
 type NewCliParams struct {
 	Converter  converters.ConverterService
 	Parser     parsers.ParserService
 	FileReader readers.FileService
+	Renderer   renderers.RendererService
+	// Storage saves the knowledge base every time an input teaches something, nothing is saved when nil.
+	Storage storages.StorageService
+	// Inputs are file locations processed in order, readers.Stdin reads the standard input.
+	Inputs []string
+	Output io.Writer
+	// Diagnostics receives the position of every unanswerable question and of
+	// every corrected typo, nothing is reported when nil.
+	Diagnostics io.Writer
+	// Hoist learns every definition of an input before answering its
+	// questions, instead of evaluating its lines in order.
+	Hoist bool
+	// Format is the way the answers are written, the sentences of Renderer when not set.
+	Format renderers.Format
+	// Rounding writes the values of the structured formats.
+	Rounding rationals.Rounding
+	// Strict makes Run return ErrUnanswered when any question cannot be
+	// answered, and stop at the first definition that cannot be learned
+	// instead of reporting it to Diagnostics.
+	Strict bool
 }
 
 func NewCli(p NewCliParams) (*cli, error) {
 
+	inputs := p.Inputs
+	if len(inputs) == 0 {
+		inputs = []string{defaultInput}
+	}
+
+	output := p.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	writer, err := renderers.NewAnswerWriter(renderers.NewAnswerWriterParams{
+		Format:   p.Format,
+		Output:   output,
+		Renderer: p.Renderer,
+		Rounding: p.Rounding,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &cli{
-		converter:  p.Converter,
-		parser:     p.Parser,
-		fileReader: p.FileReader,
+		converter:   p.Converter,
+		parser:      p.Parser,
+		fileReader:  p.FileReader,
+		renderer:    p.Renderer,
+		writer:      writer,
+		storage:     p.Storage,
+		inputs:      inputs,
+		output:      output,
+		diagnostics: p.Diagnostics,
+		hoist:       p.Hoist,
+		strict:      p.Strict,
 	}, nil
 }
 
+// Run processes every input in order, sharing the parser state between them.
+// The answers written before an error are flushed all the same. Processing
+// stops once ctx is done, the error then tells how many lines were processed.
 func (c *cli) Run(ctx context.Context) error {
 
-	lines, err := c.fileReader.ReadFile("input")
-	if err != nil {
+	for _, input := range c.inputs {
+		err := c.runInput(ctx, input)
+		if err != nil {
+			c.writer.Flush()
+			return interrupted(ctx, err, c.processed)
+		}
+	}
+
+	if err := c.writer.Flush(); err != nil {
 		return err
 	}
 
-	processedLines := []string{}
-	for _, line := range lines {
-		processedLine := c.parser.FixTypo(line)
-		processedLines = append(processedLines, processedLine)
+	if c.strict && c.unanswered > 0 {
+		return fmt.Errorf("%w, %d of %d", ErrUnanswered, c.unanswered, c.questions)
 	}
 
-	lines = processedLines
+	return nil
+}
 
-	indices := []int{}
-	for idx, line := range lines {
-		lineArr := strings.Split(line, " ")
+func (c *cli) runInput(ctx context.Context, input string) error {
 
-		found := c.parser.ParseCurrency(lineArr)
-		if found {
-			indices = append(indices, idx)
-		}
+	if !c.hoist {
+		return c.runOrdered(ctx, input)
 	}
 
-	// remove currency from params
-	for i, idx := range indices {
-		lines = append(lines[:idx-i], lines[idx+1-i:]...)
+	// hoisting needs every definition before the first question
+	lines, err := readLines(ctx, c.fileReader, input)
+	if err != nil {
+		return err
 	}
 
-	indices = []int{}
-	for idx, line := range lines {
-		lineArr := strings.Split(line, " ")
+	questions, err := c.learn(ctx, lines)
+	if err != nil {
+		return err
+	}
 
-		found, err := c.parser.ParseMetal(lineArr)
-		if err != nil {
+	for idx, line := range questions {
+		fixed, corrections := c.parser.FixTypo(line)
+		c.reportCorrections(corrections)
+		questions[idx] = fixed
+	}
+
+	answers, err := c.parser.ProcessQuestion(ctx, questions)
+	c.processed += len(answers)
+
+	if renderErr := c.render(answers); renderErr != nil {
+		return renderErr
+	}
+
+	return err
+}
+
+// runOrdered evaluates every line of input in sequence as it is read, a
+// question is answered with the definitions learned by the lines above it only.
+func (c *cli) runOrdered(ctx context.Context, input string) (err error) {
+	learned := false
+	defer func() {
+		if learned {
+			err = c.save(err)
+		}
+	}()
+
+	return streamLines(ctx, c.fileReader, input, func(line parsers.Line) error {
+		fixed, corrections := c.parser.FixTypo(line)
+		c.reportCorrections(corrections)
+
+		lineLearned, answers, err := evaluateLine(ctx, c.parser, fixed)
+		if err != nil && !reject(ctx, err, c.strict, c.diagnostics) {
 			return err
 		}
+		c.processed++
+
+		if lineLearned {
+			learned = true
+			c.reportConflicts()
+			return nil
+		}
+
+		return c.render(answers)
+	})
+}
+
+// learn hoists the definitions among lines and returns the other lines, the
+// knowledge base is saved as soon as anything was learned, even on error.
+func (c *cli) learn(ctx context.Context, lines []parsers.Line) (questions []parsers.Line, err error) {
+	learned := false
+	defer func() {
+		if learned {
+			c.reportConflicts()
+			err = c.save(err)
+		}
+	}()
+
+	// every line is corrected right before it is used so that the words
+	// learned by the previous definitions are known
+	metals := []parsers.Line{}
+	for _, line := range lines {
+		fixed, corrections := c.parser.FixTypo(line)
+		found, err := c.parser.ParseCurrency(ctx, fixed)
+		if err != nil {
+			if !reject(ctx, err, c.strict, c.diagnostics) {
+				return nil, err
+			}
+			c.processed++
+			continue
+		}
 		if found {
-			indices = append(indices, idx)
+			c.reportCorrections(corrections)
+			c.processed++
+			learned = true
+			continue
 		}
+		metals = append(metals, line)
 	}
 
-	// remove currency from params
-	for i, idx := range indices {
-		lines = append(lines[:idx-i], lines[idx+1-i:]...)
+	questions = []parsers.Line{}
+	for _, line := range metals {
+		fixed, corrections := c.parser.FixTypo(line)
+		found, err := c.parser.ParseMetal(ctx, fixed)
+		if err != nil {
+			if !reject(ctx, err, c.strict, c.diagnostics) {
+				return nil, err
+			}
+			c.processed++
+			continue
+		}
+		if found {
+			c.reportCorrections(corrections)
+			c.processed++
+			learned = true
+			continue
+		}
+		questions = append(questions, line)
+	}
+
+	return questions, nil
+}
+
+// reject reports the definition that err rejects to diagnostics, if any, and
+// whether the run goes on with the next line, it stops when strict, on a
+// conflict rejected by the error policy and once ctx is done.
+func reject(ctx context.Context, err error, strict bool, diagnostics io.Writer) bool {
+	var diagnostic *parsers.Diagnostic
+	if strict || ctx.Err() != nil || errors.Is(err, parsers.ErrConflict) || !errors.As(err, &diagnostic) {
+		return false
 	}
 
-	answers, _ := c.parser.ProcessQuestion(lines)
+	if diagnostics != nil {
+		fmt.Fprintln(diagnostics, err)
+	}
 
+	return true
+}
+
+// save writes the knowledge base to the storage, if any, and returns the first of err and the save error.
+func (c *cli) save(err error) error {
+	if c.storage == nil {
+		return err
+	}
+
+	if saveErr := c.storage.Save(c.parser.Knowledge()); err == nil {
+		return saveErr
+	}
+
+	return err
+}
+
+func (c *cli) render(answers []parsers.Answer) error {
 	for _, answer := range answers {
-		fmt.Println(answer)
+		if err := c.writer.Write(answer); err != nil {
+			return err
+		}
+
+		c.questions++
+		if answer.Err != nil {
+			c.unanswered++
+		}
+
+		if answer.Err != nil && c.diagnostics != nil {
+			fmt.Fprintln(c.diagnostics, answer.Err)
+		}
 	}
 
 	return nil
 }
+
+func (c *cli) reportCorrections(corrections []parsers.Correction) {
+	if c.diagnostics == nil {
+		return
+	}
+
+	for _, correction := range corrections {
+		fmt.Fprintln(c.diagnostics, correction)
+	}
+}
+
+// reportConflicts reports the conflicts resolved with the warn policy since the last report.
+func (c *cli) reportConflicts() {
+	if c.diagnostics == nil {
+		return
+	}
+
+	conflicts := c.parser.Conflicts()
+	for _, conflict := range conflicts[c.reportedConflicts:] {
+		if conflict.Policy == parsers.ConflictWarn {
+			fmt.Fprintf(c.diagnostics, "warning: %s\n", conflict)
+		}
+	}
+	c.reportedConflicts = len(conflicts)
+}
+
+// streamLines calls fn with every line of input along with its position as
+// soon as it is read, until ctx is done or fn returns an error. The input is
+// read apart so that a done ctx stops a read that blocks, it returns the error
+// of ctx once done even when the input is exhausted.
+func streamLines(ctx context.Context, fileReader readers.FileService, input string, fn func(line parsers.Line) error) error {
+	reader, err := fileReader.Open(input)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// stops the reading as soon as fn fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(lines)
+
+		errs <- fileReader.ReadLines(reader, func(line string) error {
+			select {
+			case lines <- line:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	number := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				if err := <-errs; err != nil {
+					return err
+				}
+				return ctx.Err()
+			}
+
+			// a line read along with the end of ctx is not evaluated
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			number++
+			err := fn(parsers.Line{
+				File:   fileName(input),
+				Number: number,
+				Text:   readers.NormalizeLine(line),
+				Raw:    line,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readLines reads every line of input along with its position, until ctx is done.
+func readLines(ctx context.Context, fileReader readers.FileService, input string) ([]parsers.Line, error) {
+	lines := []parsers.Line{}
+	err := streamLines(ctx, fileReader, input, func(line parsers.Line) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// interrupted tells how many lines were processed when err is the error of the done ctx.
+func interrupted(ctx context.Context, err error, processed int) error {
+	if ctxErr := ctx.Err(); ctxErr == nil || !errors.Is(err, ctxErr) {
+		return err
+	}
+
+	return fmt.Errorf("%w, %d lines processed", err, processed)
+}
+
+// fileName is the name of input used in diagnostics.
+func fileName(input string) string {
+	if input == readers.Stdin {
+		return "<stdin>"
+	}
+
+	return input
+}
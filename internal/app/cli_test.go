@@ -1,14 +1,30 @@
 package app_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/arieffian/roman-alien-currency/internal/app"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
 	mockConverter "github.com/arieffian/roman-alien-currency/internal/pkg/converters/mocks"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
 	mockParser "github.com/arieffian/roman-alien-currency/internal/pkg/parsers/mocks"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
 	mockReader "github.com/arieffian/roman-alien-currency/internal/pkg/readers/mocks"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+	mockRenderer "github.com/arieffian/roman-alien-currency/internal/pkg/renderers/mocks"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
+	mockStorage "github.com/arieffian/roman-alien-currency/internal/pkg/storages/mocks"
 	"github.com/go-test/deep"
 	"github.com/golang/mock/gomock"
 )
@@ -18,17 +34,25 @@ func TestCLI(t *testing.T) {
 	converter := mockConverter.NewMockConverterService(ctrl)
 	parser := mockParser.NewMockParserService(ctrl)
 	fileReader := mockReader.NewMockFileService(ctrl)
+	renderer := mockRenderer.NewMockRendererService(ctrl)
 
 	cli, _ := app.NewCli(app.NewCliParams{
 		Converter:  converter,
 		Parser:     parser,
 		FileReader: fileReader,
+		Renderer:   renderer,
+		Output:     &bytes.Buffer{},
+		Hoist:      true,
 	})
 
 	ctx := context.Background()
 
-	validResult := []string{
-		"glob is I",
+	readValidResult := func(r io.Reader, fn func(line string) error) error {
+		return fn("glob is I")
+	}
+
+	fixNothing := func(line parsers.Line) (parsers.Line, []parsers.Correction) {
+		return line, nil
 	}
 
 	type args struct {
@@ -53,28 +77,34 @@ func TestCLI(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {
 				fileReader.
 					EXPECT().
-					ReadFile(gomock.Any()).
-					Return(validResult, nil)
+					Open("input").
+					Return(io.NopCloser(strings.NewReader("")), nil)
+
+				fileReader.
+					EXPECT().
+					ReadLines(gomock.Any(), gomock.Any()).
+					DoAndReturn(readValidResult)
 
 				parser.
 					EXPECT().
 					FixTypo(gomock.Any()).
-					Return("glob is I")
+					DoAndReturn(fixNothing).
+					Times(3)
 
 				parser.
 					EXPECT().
-					ParseCurrency(gomock.Any()).
-					Return(false)
+					ParseCurrency(gomock.Any(), gomock.Any()).
+					Return(false, nil)
 
 				parser.
 					EXPECT().
-					ParseMetal(gomock.Any()).
+					ParseMetal(gomock.Any(), gomock.Any()).
 					Return(false, nil)
 
 				parser.
 					EXPECT().
-					ProcessQuestion(gomock.Any()).
-					Return([]string{}, nil)
+					ProcessQuestion(gomock.Any(), gomock.Any()).
+					Return([]parsers.Answer{}, nil)
 			},
 			want: want{
 				error: nil,
@@ -88,7 +118,7 @@ func TestCLI(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {
 				fileReader.
 					EXPECT().
-					ReadFile("input").
+					Open("input").
 					Return(nil, errors.New("error"))
 
 			},
@@ -96,6 +126,26 @@ func TestCLI(t *testing.T) {
 				error: errors.New("error"),
 			},
 		},
+		{
+			name: "when reading lines is error should return error",
+			args: args{
+				param: ctx,
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				fileReader.
+					EXPECT().
+					Open("input").
+					Return(io.NopCloser(strings.NewReader("")), nil)
+
+				fileReader.
+					EXPECT().
+					ReadLines(gomock.Any(), gomock.Any()).
+					Return(errors.New("error"))
+			},
+			want: want{
+				error: errors.New("error"),
+			},
+		},
 		{
 			name: "when parser is error should return error",
 			args: args{
@@ -104,22 +154,28 @@ func TestCLI(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {
 				fileReader.
 					EXPECT().
-					ReadFile(gomock.Any()).
-					Return(validResult, nil)
+					Open("input").
+					Return(io.NopCloser(strings.NewReader("")), nil)
+
+				fileReader.
+					EXPECT().
+					ReadLines(gomock.Any(), gomock.Any()).
+					DoAndReturn(readValidResult)
 
 				parser.
 					EXPECT().
 					FixTypo(gomock.Any()).
-					Return("glob is I")
+					DoAndReturn(fixNothing).
+					Times(2)
 
 				parser.
 					EXPECT().
-					ParseCurrency(gomock.Any()).
-					Return(false)
+					ParseCurrency(gomock.Any(), gomock.Any()).
+					Return(false, nil)
 
 				parser.
 					EXPECT().
-					ParseMetal(gomock.Any()).
+					ParseMetal(gomock.Any(), gomock.Any()).
 					Return(false, errors.New("error"))
 			},
 			want: want{
@@ -143,3 +199,801 @@ func TestCLI(t *testing.T) {
 		})
 	}
 }
+
+func TestCLIMultipleInputs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	converter := mockConverter.NewMockConverterService(ctrl)
+	parser := mockParser.NewMockParserService(ctrl)
+	fileReader := mockReader.NewMockFileService(ctrl)
+	renderer := mockRenderer.NewMockRendererService(ctrl)
+	output := &bytes.Buffer{}
+	diagnostics := &bytes.Buffer{}
+
+	cli, _ := app.NewCli(app.NewCliParams{
+		Converter:   converter,
+		Parser:      parser,
+		FileReader:  fileReader,
+		Renderer:    renderer,
+		Inputs:      []string{"definitions", "-"},
+		Output:      output,
+		Diagnostics: diagnostics,
+		Hoist:       true,
+	})
+
+	firstOpen := fileReader.
+		EXPECT().
+		Open("definitions").
+		Return(io.NopCloser(strings.NewReader("")), nil)
+
+	fileReader.
+		EXPECT().
+		Open("-").
+		Return(io.NopCloser(strings.NewReader("")), nil).After(firstOpen)
+
+	fileReader.
+		EXPECT().
+		ReadLines(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(r io.Reader, fn func(line string) error) error {
+			return fn("How much is glob ?")
+		}).
+		Times(2)
+
+	parser.
+		EXPECT().
+		FixTypo(gomock.Any()).
+		DoAndReturn(func(line parsers.Line) (parsers.Line, []parsers.Correction) {
+			if line.File != "<stdin>" {
+				return line, nil
+			}
+
+			return line, []parsers.Correction{{File: "<stdin>", Line: 1, Column: 13, From: "glb", To: "glob", Confidence: 0.75}}
+		}).
+		Times(6)
+
+	parser.
+		EXPECT().
+		ParseCurrency(gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		Times(2)
+
+	parser.
+		EXPECT().
+		ParseMetal(gomock.Any(), gomock.Any()).
+		Return(false, nil).
+		Times(2)
+
+	answer := parsers.Answer{
+		Line:     1,
+		Question: "how much is glob ?",
+		Kind:     parsers.QuestionKindHowMuch,
+		Value:    rationals.FromInt(1),
+	}
+
+	unanswered := parsers.Answer{
+		Line:     1,
+		Question: "how much is glob ?",
+		Kind:     parsers.QuestionKindHowMuch,
+		Err:      &parsers.Diagnostic{File: "<stdin>", Line: 1, Column: 13, Err: converters.ErrUnknownAlienWord},
+		Category: parsers.ErrorCategoryUnknownAlienWord,
+	}
+
+	parser.
+		EXPECT().
+		ProcessQuestion(gomock.Any(), []parsers.Line{{File: "definitions", Number: 1, Text: "how much is glob ?", Raw: "How much is glob ?"}}).
+		Return([]parsers.Answer{answer}, nil)
+
+	parser.
+		EXPECT().
+		ProcessQuestion(gomock.Any(), []parsers.Line{{File: "<stdin>", Number: 1, Text: "how much is glob ?", Raw: "How much is glob ?"}}).
+		Return([]parsers.Answer{unanswered}, nil)
+
+	renderer.
+		EXPECT().
+		Render(answer).
+		Return("glob is 1")
+
+	renderer.
+		EXPECT().
+		Render(unanswered).
+		Return("Requested number contains unknown words")
+
+	err := cli.Run(context.Background())
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+
+	if diff := deep.Equal(output.String(), "glob is 1\nRequested number contains unknown words\n"); diff != nil {
+		t.Errorf("got unexpected output.\n diff: %v\n", diff)
+	}
+
+	if diff := deep.Equal(diagnostics.String(), "<stdin>:1:13: corrected 'glb' to 'glob' (confidence 75%)\n<stdin>:1:13: unknown alien word\n"); diff != nil {
+		t.Errorf("got unexpected diagnostics.\n diff: %v\n", diff)
+	}
+}
+
+func TestCLIStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	converter := mockConverter.NewMockConverterService(ctrl)
+	parser := mockParser.NewMockParserService(ctrl)
+	fileReader := mockReader.NewMockFileService(ctrl)
+	renderer := mockRenderer.NewMockRendererService(ctrl)
+	storage := mockStorage.NewMockStorageService(ctrl)
+
+	cli, _ := app.NewCli(app.NewCliParams{
+		Converter:  converter,
+		Parser:     parser,
+		FileReader: fileReader,
+		Renderer:   renderer,
+		Storage:    storage,
+		Output:     &bytes.Buffer{},
+		Hoist:      true,
+	})
+
+	knowledge := storages.KnowledgeBase{
+		Dictionary: map[string]string{"glob": "i"},
+		Prices:     map[string]rationals.Rational{},
+	}
+
+	fixNothing := func(line parsers.Line) (parsers.Line, []parsers.Correction) {
+		return line, nil
+	}
+
+	type args struct {
+		learned bool
+	}
+
+	type want struct {
+		error error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when definition is learned should save the knowledge base",
+			args: args{
+				learned: true,
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					Knowledge().
+					Return(knowledge)
+
+				storage.
+					EXPECT().
+					Save(knowledge).
+					Return(nil)
+
+				parser.
+					EXPECT().
+					ProcessQuestion(gomock.Any(), []parsers.Line{}).
+					Return([]parsers.Answer{}, nil)
+			},
+			want: want{
+				error: nil,
+			},
+		},
+		{
+			name: "when saving is error should return error",
+			args: args{
+				learned: true,
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					Knowledge().
+					Return(knowledge)
+
+				storage.
+					EXPECT().
+					Save(knowledge).
+					Return(errors.New("error"))
+			},
+			want: want{
+				error: errors.New("error"),
+			},
+		},
+		{
+			name: "when nothing is learned should not save",
+			args: args{
+				learned: false,
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					ParseMetal(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+
+				parser.
+					EXPECT().
+					FixTypo(gomock.Any()).
+					DoAndReturn(fixNothing).
+					Times(2)
+
+				parser.
+					EXPECT().
+					ProcessQuestion(gomock.Any(), gomock.Any()).
+					Return([]parsers.Answer{}, nil)
+			},
+			want: want{
+				error: nil,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			fileReader.
+				EXPECT().
+				Open("input").
+				Return(io.NopCloser(strings.NewReader("")), nil)
+
+			fileReader.
+				EXPECT().
+				ReadLines(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(r io.Reader, fn func(line string) error) error {
+					return fn("glob is I")
+				})
+
+			parser.
+				EXPECT().
+				FixTypo(gomock.Any()).
+				DoAndReturn(fixNothing)
+
+			parser.
+				EXPECT().
+				ParseCurrency(gomock.Any(), gomock.Any()).
+				Return(tc.args.learned, nil)
+
+			err := cli.Run(context.Background())
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCLIOrdered(t *testing.T) {
+
+	input := filepath.Join(t.TempDir(), "input")
+	err := os.WriteFile(input, []byte("how much is glob ?\nglob is I\nhow much is glob ?\nglob is V\nhow much is glob ?\n"), 0o600)
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+
+	type args struct {
+		hoist  bool
+		strict bool
+	}
+
+	type want struct {
+		output string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when lines are evaluated in order should answer with the definitions above",
+			args: args{
+				hoist: false,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "Requested number contains unknown words\nglob is 1\nglob is 5\n",
+			},
+		},
+		{
+			name: "when definitions are hoisted should answer with the last definitions",
+			args: args{
+				hoist: true,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "glob is 5\nglob is 5\nglob is 5\n",
+			},
+		},
+		{
+			name: "when strict and a question is unanswerable should return error after the answers",
+			args: args{
+				strict: true,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "Requested number contains unknown words\nglob is 1\nglob is 5\n",
+				error:  errors.New("unanswerable questions, 1 of 3"),
+			},
+		},
+		{
+			name: "when strict and every question is answered should not return error",
+			args: args{
+				hoist:  true,
+				strict: true,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "glob is 5\nglob is 5\nglob is 5\n",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			converter := converters.NewConverter(converters.NewConverterParams{})
+			output := &bytes.Buffer{}
+
+			cli, _ := app.NewCli(app.NewCliParams{
+				Converter: converter,
+				Parser: parsers.NewParser(parsers.NewParserParams{
+					Converter:       converter,
+					AlienDictionary: map[string]string{},
+					MetalValue:      map[string]rationals.Rational{},
+					ConflictPolicy:  parsers.ConflictLastWins,
+				}),
+				FileReader: readers.NewFile(),
+				Renderer:   renderers.NewText(renderers.NewTextParams{}),
+				Inputs:     []string{input},
+				Output:     output,
+				Hoist:      tc.args.hoist,
+				Strict:     tc.args.strict,
+			})
+
+			err := cli.Run(context.Background())
+			if tc.want.error == nil && err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if tc.want.error != nil {
+				if err == nil || err.Error() != tc.want.error.Error() {
+					t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", tc.want.error, err)
+				}
+				if !errors.Is(err, app.ErrUnanswered) {
+					t.Errorf("expected error to wrap app.ErrUnanswered, got %v", err)
+				}
+			}
+
+			if diff := deep.Equal(output.String(), tc.want.output); diff != nil {
+				t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", tc.want.output, output.String(), diff)
+			}
+		})
+
+	}
+}
+
+// notifyingWriter closes written on its first write.
+type notifyingWriter struct {
+	bytes.Buffer
+	once    sync.Once
+	written chan struct{}
+}
+
+func (w *notifyingWriter) Write(p []byte) (int, error) {
+	defer w.once.Do(func() { close(w.written) })
+
+	return w.Buffer.Write(p)
+}
+
+func TestCLIStreaming(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reader, writer := io.Pipe()
+	output := &notifyingWriter{written: make(chan struct{})}
+
+	fileReader := mockReader.NewMockFileService(ctrl)
+	fileReader.
+		EXPECT().
+		Open("input").
+		Return(reader, nil)
+
+	fileReader.
+		EXPECT().
+		ReadLines(gomock.Any(), gomock.Any()).
+		DoAndReturn(readers.NewFile().ReadLines)
+
+	converter := converters.NewConverter(converters.NewConverterParams{})
+	cli, _ := app.NewCli(app.NewCliParams{
+		Converter: converter,
+		Parser: parsers.NewParser(parsers.NewParserParams{
+			Converter:       converter,
+			AlienDictionary: map[string]string{},
+			MetalValue:      map[string]rationals.Rational{},
+		}),
+		FileReader: fileReader,
+		Renderer:   renderers.NewText(renderers.NewTextParams{}),
+		Output:     output,
+	})
+
+	// the last line is only written once the first question is answered
+	go func() {
+		fmt.Fprint(writer, "glob is I\nhow much is glob ?\n")
+		select {
+		case <-output.written:
+			fmt.Fprint(writer, "how much is glob glob ?\n")
+		case <-time.After(time.Second):
+		}
+		writer.Close()
+	}()
+
+	err := cli.Run(context.Background())
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+
+	expected := "glob is 1\nglob glob is 2\n"
+	if diff := deep.Equal(output.String(), expected); diff != nil {
+		t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", expected, output.String(), diff)
+	}
+}
+
+func TestCLIBlockedInput(t *testing.T) {
+
+	type args struct {
+		ctx    func() (context.Context, context.CancelFunc)
+		reader func() io.ReadCloser
+	}
+
+	type want struct {
+		output string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when context is canceled while a read blocks should stop the run",
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					ctx, cancel := context.WithCancel(context.Background())
+					time.AfterFunc(50*time.Millisecond, cancel)
+					return ctx, cancel
+				},
+				reader: func() io.ReadCloser {
+					reader, writer := io.Pipe()
+					go fmt.Fprint(writer, "glob is I\n")
+					return reader
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "",
+				error:  errors.New("context canceled, 1 lines processed"),
+			},
+		},
+		{
+			name: "when deadline is exceeded while a read blocks should stop the run",
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.WithTimeout(context.Background(), 50*time.Millisecond)
+				},
+				reader: func() io.ReadCloser {
+					reader, _ := io.Pipe()
+					return reader
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "",
+				error:  errors.New("context deadline exceeded, 0 lines processed"),
+			},
+		},
+		{
+			name: "when context is canceled by the last line should return its error",
+			args: args{
+				ctx: func() (context.Context, context.CancelFunc) {
+					return context.WithCancel(context.Background())
+				},
+				reader: func() io.ReadCloser {
+					return io.NopCloser(strings.NewReader("glob is I\nhow much is glob ?\n"))
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "glob is 1\n",
+				error:  errors.New("context canceled, 2 lines processed"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx, cancel := tc.args.ctx()
+			defer cancel()
+
+			fileReader := mockReader.NewMockFileService(ctrl)
+			fileReader.
+				EXPECT().
+				Open("input").
+				Return(tc.args.reader(), nil)
+
+			fileReader.
+				EXPECT().
+				ReadLines(gomock.Any(), gomock.Any()).
+				DoAndReturn(readers.NewFile().ReadLines).
+				AnyTimes()
+
+			converter := converters.NewConverter(converters.NewConverterParams{})
+			output := &bytes.Buffer{}
+
+			cli, _ := app.NewCli(app.NewCliParams{
+				Converter: converter,
+				Parser: cancelingParser{
+					ParserService: parsers.NewParser(parsers.NewParserParams{
+						Converter:       converter,
+						AlienDictionary: map[string]string{},
+						MetalValue:      map[string]rationals.Rational{},
+					}),
+					cancel: cancel,
+				},
+				FileReader: fileReader,
+				Renderer:   renderers.NewText(renderers.NewTextParams{}),
+				Output:     output,
+			})
+
+			err := cli.Run(ctx)
+			if err == nil || err.Error() != tc.want.error.Error() {
+				t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", tc.want.error, err)
+			}
+
+			if diff := deep.Equal(output.String(), tc.want.output); diff != nil {
+				t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", tc.want.output, output.String(), diff)
+			}
+		})
+
+	}
+}
+
+func TestCLIRejectedDefinitions(t *testing.T) {
+
+	input := filepath.Join(t.TempDir(), "input")
+	err := os.WriteFile(input, []byte("glob is I\nglob zzz Gold is 100 Credits\nglob Gold is 100 Zorbs\nglob Gold is 50 Credits\nglob Gold is 60 Credits\nhow many Credits is glob Gold ?\n"), 0o600)
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+
+	type args struct {
+		hoist          bool
+		strict         bool
+		conflictPolicy parsers.ConflictPolicy
+	}
+
+	type want struct {
+		output      string
+		diagnostics string
+		error       error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when definition cannot be learned should report it and answer the next questions",
+			args: args{
+				conflictPolicy: parsers.ConflictLastWins,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output:      "glob gold is 60 Credits\n",
+				diagnostics: input + ":2:6: unknown alien word 'zzz'\n" + input + ":3:18: unknown currency 'zorbs'\n",
+			},
+		},
+		{
+			name: "when hoisted definition cannot be learned should report it and answer the questions",
+			args: args{
+				hoist:          true,
+				conflictPolicy: parsers.ConflictLastWins,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output:      "glob gold is 60 Credits\n",
+				diagnostics: input + ":2:6: unknown alien word 'zzz'\n" + input + ":3:18: unknown currency 'zorbs'\n",
+			},
+		},
+		{
+			name: "when strict and definition cannot be learned should return error",
+			args: args{
+				strict:         true,
+				conflictPolicy: parsers.ConflictLastWins,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "",
+				error:  errors.New(input + ":2:6: unknown alien word 'zzz'"),
+			},
+		},
+		{
+			name: "when conflict is rejected by the error policy should return error",
+			args: args{
+				conflictPolicy: parsers.ConflictError,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output:      "",
+				diagnostics: input + ":2:6: unknown alien word 'zzz'\n" + input + ":3:18: unknown currency 'zorbs'\n",
+				error:       errors.New(input + ":5:6: conflicting definition, price of 'gold' is redefined as 60 Credits by \"glob gold is 60 credits\", it was 50 Credits by " + input + ":4:6 \"glob gold is 50 credits\""),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			converter := converters.NewConverter(converters.NewConverterParams{})
+			output := &bytes.Buffer{}
+			diagnostics := &bytes.Buffer{}
+
+			cli, _ := app.NewCli(app.NewCliParams{
+				Converter: converter,
+				Parser: parsers.NewParser(parsers.NewParserParams{
+					Converter:       converter,
+					AlienDictionary: map[string]string{},
+					MetalValue:      map[string]rationals.Rational{},
+					ConflictPolicy:  tc.args.conflictPolicy,
+				}),
+				FileReader:  readers.NewFile(),
+				Renderer:    renderers.NewText(renderers.NewTextParams{}),
+				Inputs:      []string{input},
+				Output:      output,
+				Diagnostics: diagnostics,
+				Hoist:       tc.args.hoist,
+				Strict:      tc.args.strict,
+			})
+
+			err := cli.Run(context.Background())
+			if tc.want.error == nil && err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if tc.want.error != nil && (err == nil || err.Error() != tc.want.error.Error()) {
+				t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", tc.want.error, err)
+			}
+
+			if diff := deep.Equal(output.String(), tc.want.output); diff != nil {
+				t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", tc.want.output, output.String(), diff)
+			}
+
+			if diff := deep.Equal(diagnostics.String(), tc.want.diagnostics); diff != nil {
+				t.Errorf("got unexpected diagnostics.\n expected: %q\n actual: %q\n diff: %v\n", tc.want.diagnostics, diagnostics.String(), diff)
+			}
+		})
+
+	}
+}
+
+// cancelingParser cancels the run as soon as it answers a question.
+type cancelingParser struct {
+	parsers.ParserService
+	cancel context.CancelFunc
+}
+
+func (p cancelingParser) ProcessQuestion(ctx context.Context, questions []parsers.Line) ([]parsers.Answer, error) {
+	defer p.cancel()
+
+	return p.ParserService.ProcessQuestion(ctx, questions)
+}
+
+func TestCLICanceled(t *testing.T) {
+
+	input := filepath.Join(t.TempDir(), "input")
+	err := os.WriteFile(input, []byte("glob is I\nhow much is glob ?\nhow much is glob ?\n"), 0o600)
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+
+	type args struct {
+		hoist bool
+	}
+
+	type want struct {
+		output string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when context is canceled while evaluating lines in order should stop at the next line",
+			args: args{
+				hoist: false,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "glob is 1\n",
+				error:  errors.New("context canceled, 2 lines processed"),
+			},
+		},
+		{
+			name: "when context is canceled while answering hoisted questions should keep the answers given",
+			args: args{
+				hoist: true,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "glob is 1\nglob is 1\n",
+				error:  nil,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			converter := converters.NewConverter(converters.NewConverterParams{})
+			output := &bytes.Buffer{}
+
+			cli, _ := app.NewCli(app.NewCliParams{
+				Converter: converter,
+				Parser: cancelingParser{
+					ParserService: parsers.NewParser(parsers.NewParserParams{
+						Converter:       converter,
+						AlienDictionary: map[string]string{},
+						MetalValue:      map[string]rationals.Rational{},
+					}),
+					cancel: cancel,
+				},
+				FileReader: readers.NewFile(),
+				Renderer:   renderers.NewText(renderers.NewTextParams{}),
+				Inputs:     []string{input},
+				Output:     output,
+				Hoist:      tc.args.hoist,
+			})
+
+			err := cli.Run(ctx)
+			if tc.want.error == nil && err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if tc.want.error != nil {
+				if err == nil || err.Error() != tc.want.error.Error() {
+					t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", tc.want.error, err)
+				}
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("expected error to wrap context.Canceled, got %v", err)
+				}
+			}
+
+			if diff := deep.Equal(output.String(), tc.want.output); diff != nil {
+				t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", tc.want.output, output.String(), diff)
+			}
+		})
+
+	}
+}
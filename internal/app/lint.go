@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+)
+
+var ErrLintFailed = errors.New("problems found")
+
+type linter struct {
+	parser     parsers.ParserService
+	fileReader readers.FileService
+	inputs     []string
+	output     io.Writer
+	// reportedConflicts is the number of conflicts already reported.
+	reportedConflicts int
+	problems          int
+	// processed counts the lines checked so far.
+	processed int
+}
+
+type NewLinterParams struct {
+	Parser     parsers.ParserService
+	FileReader readers.FileService
+	// Inputs are file locations checked in order, readers.Stdin reads the standard input.
+	Inputs []string
+	// Output receives every problem along with its position.
+	Output io.Writer
+}
+
+func NewLinter(p NewLinterParams) (*linter, error) {
+
+	inputs := p.Inputs
+	if len(inputs) == 0 {
+		inputs = []string{defaultInput}
+	}
+
+	output := p.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	return &linter{
+		parser:     p.Parser,
+		fileReader: p.FileReader,
+		inputs:     inputs,
+		output:     output,
+	}, nil
+}
+
+// Run evaluates every line of the inputs in order, like the cli does, and
+// reports the corrected typos, the rejected definitions, the conflicts and the
+// unanswerable questions instead of the answers. ErrLintFailed is returned
+// when any problem is found. The lint stops once ctx is done, the error then
+// tells how many lines were checked.
+func (l *linter) Run(ctx context.Context) error {
+
+	for _, input := range l.inputs {
+		err := streamLines(ctx, l.fileReader, input, func(line parsers.Line) error {
+			l.lintLine(ctx, line)
+			l.processed++
+			return nil
+		})
+		if err != nil {
+			return interrupted(ctx, err, l.processed)
+		}
+	}
+
+	if l.problems > 0 {
+		fmt.Fprintf(l.output, "%d problems found\n", l.problems)
+		return fmt.Errorf("%w, %d", ErrLintFailed, l.problems)
+	}
+
+	return nil
+}
+
+func (l *linter) lintLine(ctx context.Context, line parsers.Line) {
+	fixed, corrections := l.parser.FixTypo(line)
+	for _, correction := range corrections {
+		l.report(correction)
+	}
+
+	// a rejected definition does not stop the lint, the next lines are checked all the same
+	_, answers, err := evaluateLine(ctx, l.parser, fixed)
+	if err != nil && ctx.Err() == nil {
+		l.report(err)
+	}
+
+	conflicts := l.parser.Conflicts()
+	for _, conflict := range conflicts[l.reportedConflicts:] {
+		// the error policy already rejected the definition with the conflict
+		if conflict.Policy != parsers.ConflictError {
+			l.report(conflict)
+		}
+	}
+	l.reportedConflicts = len(conflicts)
+
+	for _, answer := range answers {
+		if answer.Err != nil {
+			l.report(answer.Err)
+		}
+	}
+}
+
+func (l *linter) report(problem interface{}) {
+	l.problems++
+	fmt.Fprintln(l.output, problem)
+}
@@ -0,0 +1,122 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/app"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/go-test/deep"
+)
+
+func TestLinter(t *testing.T) {
+
+	type args struct {
+		content string
+		policy  parsers.ConflictPolicy
+	}
+
+	type want struct {
+		output string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when there is no problem should report nothing",
+			args: args{
+				content: "glob is I\nglob glob Silver is 34 Credits\nhow much is glob ?\n",
+				policy:  parsers.ConflictWarn,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "",
+			},
+		},
+		{
+			name: "when there are problems should report every one of them",
+			args: args{
+				content: "glob is I\nglob is V\nhow much is glob prk ?\nhow much wood ?\nhow much isglob ?\n",
+				policy:  parsers.ConflictWarn,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "{input}:2:1: 'glob' is redefined as 'v' by \"glob is v\", it was 'i' by {input}:1:1 \"glob is i\", the last definition is kept\n" +
+					"{input}:3:18: unknown alien word 'prk'\n" +
+					"{input}:4:10: expected 'is', found 'wood'\n" +
+					"{input}:5:10: corrected 'isglob' to 'is glob' (confidence 100%)\n" +
+					"4 problems found\n",
+				error: errors.New("problems found, 4"),
+			},
+		},
+		{
+			name: "when a definition is rejected should check the next lines",
+			args: args{
+				content: "glob is I\nglob is V\nhow much is glob ?\nhow much is prok ?\n",
+				policy:  parsers.ConflictError,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "{input}:2:1: conflicting definition, 'glob' is redefined as 'v' by \"glob is v\", it was 'i' by {input}:1:1 \"glob is i\"\n" +
+					"{input}:4:13: unknown alien word 'prok'\n" +
+					"2 problems found\n",
+				error: errors.New("problems found, 2"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			input := filepath.Join(t.TempDir(), "input")
+			err := os.WriteFile(input, []byte(tc.args.content), 0o600)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			converter := converters.NewConverter(converters.NewConverterParams{})
+			output := &bytes.Buffer{}
+
+			linter, _ := app.NewLinter(app.NewLinterParams{
+				Parser: parsers.NewParser(parsers.NewParserParams{
+					Converter:       converter,
+					AlienDictionary: map[string]string{},
+					MetalValue:      map[string]rationals.Rational{},
+					ConflictPolicy:  tc.args.policy,
+				}),
+				FileReader: readers.NewFile(),
+				Inputs:     []string{input},
+				Output:     output,
+			})
+
+			err = linter.Run(context.Background())
+			if tc.want.error == nil && err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if tc.want.error != nil && (err == nil || err.Error() != tc.want.error.Error()) {
+				t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", tc.want.error, err)
+			}
+
+			expected := strings.ReplaceAll(tc.want.output, "{input}", input)
+			if diff := deep.Equal(output.String(), expected); diff != nil {
+				t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", expected, output.String(), diff)
+			}
+		})
+
+	}
+}
@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+)
+
+// undatedPrice stands for the date of a price without date in the history.
+const undatedPrice = "undated"
+
+type priceHistory struct {
+	parser      parsers.ParserService
+	fileReader  readers.FileService
+	inputs      []string
+	commodity   string
+	output      io.Writer
+	diagnostics io.Writer
+	rounding    rationals.Rounding
+	strict      bool
+	// processed counts the lines learned so far.
+	processed int
+}
+
+type NewPriceHistoryParams struct {
+	Parser     parsers.ParserService
+	FileReader readers.FileService
+	// Inputs are file locations learned in order, readers.Stdin reads the standard input.
+	Inputs []string
+	// Commodity is the commodity whose prices are printed, ignoring case.
+	Commodity string
+	// Output receives a line per price.
+	Output io.Writer
+	// Diagnostics receives the definitions that cannot be learned, nothing is reported when nil.
+	Diagnostics io.Writer
+	// Rounding writes the prices and their changes, rationals.DefaultRounding when its mode is not set.
+	Rounding rationals.Rounding
+	// Strict stops Run at the first definition that cannot be learned instead
+	// of reporting it to Diagnostics.
+	Strict bool
+}
+
+func NewPriceHistory(p NewPriceHistoryParams) (*priceHistory, error) {
+
+	inputs := p.Inputs
+	if len(inputs) == 0 {
+		inputs = []string{defaultInput}
+	}
+
+	output := p.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	rounding := p.Rounding
+	if rounding.Mode == "" {
+		rounding = rationals.DefaultRounding
+	}
+
+	return &priceHistory{
+		parser:      p.Parser,
+		fileReader:  p.FileReader,
+		inputs:      inputs,
+		commodity:   p.Commodity,
+		output:      output,
+		diagnostics: p.Diagnostics,
+		rounding:    rounding,
+		strict:      p.Strict,
+	}, nil
+}
+
+// Run learns every line of the inputs in order, like the cli does without
+// writing the answers, then prints every price of the commodity from the
+// oldest one, along with its change from the previous price in percent. The
+// price without date comes first. A definition that cannot be learned is
+// reported and skipped unless strict. The history stops once ctx is done, the
+// error then tells how many lines were learned.
+func (h *priceHistory) Run(ctx context.Context) error {
+
+	for _, input := range h.inputs {
+		err := streamLines(ctx, h.fileReader, input, func(line parsers.Line) error {
+			fixed, _ := h.parser.FixTypo(line)
+			if _, _, err := evaluateLine(ctx, h.parser, fixed); err != nil && !reject(ctx, err, h.strict, h.diagnostics) {
+				return err
+			}
+			h.processed++
+			return nil
+		})
+		if err != nil {
+			return interrupted(ctx, err, h.processed)
+		}
+	}
+
+	dates, prices, err := h.prices()
+	if err != nil {
+		return err
+	}
+
+	for idx, date := range dates {
+		line := fmt.Sprintf("%s %s Credits", date, h.rounding.Format(prices[idx]))
+
+		// a change from a free commodity has no percentage
+		if idx > 0 && prices[idx-1].Sign() != 0 {
+			line += " " + h.change(prices[idx-1], prices[idx])
+		}
+
+		fmt.Fprintln(h.output, line)
+	}
+
+	return nil
+}
+
+// prices returns the prices of the commodity sorted by date, the price without date first.
+func (h *priceHistory) prices() ([]string, []rationals.Rational, error) {
+	knowledge := h.parser.Knowledge()
+
+	dates := []string{}
+	prices := []rationals.Rational{}
+	found := false
+
+	for commodity, price := range knowledge.Prices {
+		if strings.EqualFold(commodity, h.commodity) {
+			dates = append(dates, undatedPrice)
+			prices = append(prices, price)
+			found = true
+		}
+	}
+
+	for commodity, history := range knowledge.History {
+		if !strings.EqualFold(commodity, h.commodity) {
+			continue
+		}
+
+		found = true
+
+		sorted := make([]string, 0, len(history))
+		for date := range history {
+			sorted = append(sorted, date)
+		}
+		slices.Sort(sorted)
+
+		for _, date := range sorted {
+			dates = append(dates, date)
+			prices = append(prices, history[date])
+		}
+	}
+
+	if !found {
+		return nil, nil, fmt.Errorf("%w '%s'", parsers.ErrUnknownCommodity, h.commodity)
+	}
+
+	return dates, prices, nil
+}
+
+// change writes the change from previous to current in percent, such as +3.8%.
+func (h *priceHistory) change(previous rationals.Rational, current rationals.Rational) string {
+	change := current.Sub(previous).Quo(previous).Mul(rationals.FromInt(100))
+
+	sign := ""
+	if change.Sign() > 0 {
+		sign = "+"
+	}
+
+	// a percentage is a decimal number whatever the rounding of the prices
+	return sign + h.rounding.Decimal().Format(change) + "%"
+}
@@ -0,0 +1,140 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/app"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/go-test/deep"
+)
+
+func TestPriceHistory(t *testing.T) {
+
+	type args struct {
+		content   string
+		commodity string
+		strict    bool
+	}
+
+	type want struct {
+		output      string
+		diagnostics string
+		error       error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when commodity has dated prices should print them by date with their changes",
+			args: args{
+				content: "glob is I\nprok is V\nglob glob Gold is 28000 Credits\n" +
+					"on 3021-06-01 glob Gold is 15000 Credits\non 3021-05-01 glob prok Gold is 57800 Credits\n" +
+					"on 3021-07-01 glob Gold is 14000 Credits\nhow many Credits is glob Gold ?\n",
+				commodity: "Gold",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "undated 14000 Credits\n3021-05-01 14450 Credits +3.2%\n3021-06-01 15000 Credits +3.8%\n3021-07-01 14000 Credits -6.7%\n",
+			},
+		},
+		{
+			name: "when definition cannot be learned should report it and go on",
+			args: args{
+				content:   "glob is I\nglob Gold is 10 Credits\nglob Gold is -5 Credits\non 3021-05-01 glob Gold is 12 Credits\n",
+				commodity: "gold",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output:      "undated 10 Credits\n3021-05-01 12 Credits +20%\n",
+				diagnostics: "input:3:14: invalid number '-5'\n",
+			},
+		},
+		{
+			name: "when definition cannot be learned in strict mode should return error",
+			args: args{
+				content:   "glob is I\nglob Gold is 10 Credits\nglob Gold is -5 Credits\non 3021-05-01 glob Gold is 12 Credits\n",
+				commodity: "gold",
+				strict:    true,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "",
+				error:  errors.New("input:3:14: invalid number '-5'"),
+			},
+		},
+		{
+			name: "when commodity has no price should return error",
+			args: args{
+				content:   "glob is I\nglob Gold is 14450 Credits\n",
+				commodity: "iron",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "",
+				error:  errors.New("unknown commodity 'iron'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			input := filepath.Join(t.TempDir(), "input")
+			err := os.WriteFile(input, []byte(tc.args.content), 0o600)
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			output := &bytes.Buffer{}
+			diagnostics := &bytes.Buffer{}
+
+			history, _ := app.NewPriceHistory(app.NewPriceHistoryParams{
+				Parser: parsers.NewParser(parsers.NewParserParams{
+					Converter:       converters.NewConverter(converters.NewConverterParams{}),
+					AlienDictionary: map[string]string{},
+					MetalValue:      map[string]rationals.Rational{},
+				}),
+				FileReader:  readers.NewFile(),
+				Inputs:      []string{input},
+				Commodity:   tc.args.commodity,
+				Output:      output,
+				Diagnostics: diagnostics,
+				Strict:      tc.args.strict,
+			})
+
+			// the errors are located in the temporary input
+			err = history.Run(context.Background())
+			if tc.want.error == nil && err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if tc.want.error != nil && (err == nil || strings.ReplaceAll(err.Error(), input, "input") != tc.want.error.Error()) {
+				t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", tc.want.error, err)
+			}
+
+			reported := strings.ReplaceAll(diagnostics.String(), input, "input")
+			if diff := deep.Equal(reported, tc.want.diagnostics); diff != nil {
+				t.Errorf("got unexpected diagnostics.\n expected: %q\n actual: %q\n diff: %v\n", tc.want.diagnostics, reported, diff)
+			}
+
+			if diff := deep.Equal(output.String(), tc.want.output); diff != nil {
+				t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", tc.want.output, output.String(), diff)
+			}
+		})
+
+	}
+}
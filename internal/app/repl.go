@@ -0,0 +1,362 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
+)
+
+const (
+	replFile   = "repl"
+	replPrompt = "> "
+	replHelp   = `statements are learned and questions are answered as soon as they are typed
+meta-commands:
+  :dict          show the learned alien words of every dialect
+  :metals        show the learned metal prices
+  :rates         show the learned exchange rates
+  :history       show the statements typed so far
+  :conflicts     show every definition that contradicted an earlier one
+  :reset         forget every alien word and metal price
+  :load <file>   evaluate every line of a file
+  :export <file> write the learned alien words and prices to a JSON file
+  :import <file> replace the learned alien words and prices by a JSON file
+  :help          show this help
+  :quit          leave the repl`
+)
+
+type repl struct {
+	parser     parsers.ParserService
+	fileReader readers.FileService
+	renderer   renderers.RendererService
+	storage    storages.StorageService
+	rounding   rationals.Rounding
+	input      io.Reader
+	output     io.Writer
+	history    []string
+	// reportedConflicts is the number of conflicts already printed.
+	reportedConflicts int
+}
+
+type NewReplParams struct {
+	Parser     parsers.ParserService
+	FileReader readers.FileService
+	Renderer   renderers.RendererService
+	// Storage saves the knowledge base every time it changes, nothing is saved when nil.
+	Storage storages.StorageService
+	// Rounding writes the prices and rates, rationals.DefaultRounding when its mode is not set.
+	Rounding rationals.Rounding
+	Input    io.Reader
+	Output   io.Writer
+}
+
+func NewRepl(p NewReplParams) (*repl, error) {
+
+	input := p.Input
+	if input == nil {
+		input = os.Stdin
+	}
+
+	output := p.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	rounding := p.Rounding
+	if rounding.Mode == "" {
+		rounding = rationals.DefaultRounding
+	}
+
+	return &repl{
+		parser:     p.Parser,
+		fileReader: p.FileReader,
+		renderer:   p.Renderer,
+		storage:    p.Storage,
+		rounding:   rounding,
+		input:      input,
+		output:     output,
+	}, nil
+}
+
+// Run reads the input line by line until it is exhausted, :quit is typed or
+// ctx is done, printing every answer as soon as its line is evaluated.
+func (r *repl) Run(ctx context.Context) error {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines, errs := scanLines(ctx, r.input)
+
+	fmt.Fprint(r.output, replPrompt)
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(r.output)
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				fmt.Fprintln(r.output)
+				return <-errs
+			}
+
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, ":") {
+				quit := r.runMetaCommand(ctx, line)
+				if quit {
+					return nil
+				}
+			} else if line != "" {
+				r.history = append(r.history, line)
+				r.evaluate(ctx, replFile, len(r.history), line)
+			}
+
+			fmt.Fprint(r.output, replPrompt)
+		}
+	}
+}
+
+// scanLines reads input in the background so that waiting for a line never
+// delays the end of ctx. errs receives the error of the scan before lines is closed.
+func scanLines(ctx context.Context, input io.Reader) (<-chan string, <-chan error) {
+	lines := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(input)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+		errs <- scanner.Err()
+	}()
+
+	return lines, errs
+}
+
+func (r *repl) runMetaCommand(ctx context.Context, line string) bool {
+	command, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch command {
+	case ":dict":
+		dictionary := r.parser.AlienDictionary()
+		for _, alien := range sortedKeys(dictionary) {
+			fmt.Fprintf(r.output, "%s is %s\n", alien, strings.ToUpper(dictionary[alien]))
+		}
+
+		dialects := r.parser.Knowledge().Dialects
+		for _, dialect := range sortedKeys(dialects) {
+			for _, alien := range sortedKeys(dialects[dialect]) {
+				fmt.Fprintf(r.output, "on %s %s is %s\n", dialect, alien, strings.ToUpper(dialects[dialect][alien]))
+			}
+		}
+	case ":metals":
+		metalValue := r.parser.MetalValue()
+		for _, metal := range sortedKeys(metalValue) {
+			fmt.Fprintf(r.output, "%s is %s Credits\n", metal, r.rounding.Format(metalValue[metal]))
+		}
+	case ":rates":
+		for _, rate := range r.parser.Knowledge().Rates {
+			fmt.Fprintf(r.output, "1 %s is %s %s\n", rate.From, r.rounding.Format(rate.Rate), rate.To)
+		}
+	case ":history":
+		for idx, statement := range r.history {
+			fmt.Fprintf(r.output, "%d  %s\n", idx+1, statement)
+		}
+	case ":conflicts":
+		for _, conflict := range r.parser.Conflicts() {
+			fmt.Fprintln(r.output, conflict)
+		}
+	case ":reset":
+		r.parser.Reset()
+		r.reportedConflicts = 0
+		r.save()
+	case ":load":
+		if arg == "" {
+			fmt.Fprintln(r.output, "usage: :load <file>")
+			break
+		}
+		r.load(ctx, arg)
+	case ":export":
+		if arg == "" {
+			fmt.Fprintln(r.output, "usage: :export <file>")
+			break
+		}
+		r.exportKnowledge(arg)
+	case ":import":
+		if arg == "" {
+			fmt.Fprintln(r.output, "usage: :import <file>")
+			break
+		}
+		r.importKnowledge(arg)
+	case ":help":
+		fmt.Fprintln(r.output, replHelp)
+	case ":quit", ":q":
+		return true
+	default:
+		fmt.Fprintf(r.output, "unknown command %s, type :help for the list of commands\n", command)
+	}
+
+	return false
+}
+
+func (r *repl) load(ctx context.Context, fileLoc string) {
+	file, err := r.fileReader.Open(fileLoc)
+	if err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+		return
+	}
+	defer file.Close()
+
+	number := 0
+	err = r.fileReader.ReadLines(file, func(line string) error {
+		number++
+		r.evaluate(ctx, fileLoc, number, line)
+		return ctx.Err()
+	})
+	if err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+	}
+}
+
+func (r *repl) evaluate(ctx context.Context, file string, number int, line string) {
+	fixed, corrections := r.parser.FixTypo(parsers.Line{
+		File:   file,
+		Number: number,
+		Text:   readers.NormalizeLine(line),
+		Raw:    line,
+	})
+	for _, correction := range corrections {
+		fmt.Fprintf(r.output, "note: %s\n", correction)
+	}
+
+	learned, answers, err := evaluateLine(ctx, r.parser, fixed)
+	if learned {
+		r.warnConflicts()
+		r.save()
+	}
+	if err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+		return
+	}
+
+	for _, answer := range answers {
+		fmt.Fprintln(r.output, r.renderer.Render(answer))
+
+		if answer.Err != nil {
+			fmt.Fprintf(r.output, "error: %s\n", answer.Err)
+		}
+	}
+}
+
+// evaluateLine learns line when it is a definition, otherwise it answers line as a question.
+func evaluateLine(ctx context.Context, parser parsers.ParserService, line parsers.Line) (bool, []parsers.Answer, error) {
+	if line.Text == "" {
+		return false, nil, nil
+	}
+
+	found, err := parser.ParseCurrency(ctx, line)
+	if err != nil {
+		return false, nil, err
+	}
+	if found {
+		return true, nil, nil
+	}
+
+	found, err = parser.ParseMetal(ctx, line)
+	if err != nil {
+		return false, nil, err
+	}
+	if found {
+		return true, nil, nil
+	}
+
+	answers, err := parser.ProcessQuestion(ctx, []parsers.Line{line})
+
+	return false, answers, err
+}
+
+// warnConflicts prints the conflicts resolved with the warn policy by the last definition.
+func (r *repl) warnConflicts() {
+	conflicts := r.parser.Conflicts()
+	for _, conflict := range conflicts[r.reportedConflicts:] {
+		if conflict.Policy == parsers.ConflictWarn {
+			fmt.Fprintf(r.output, "warning: %s\n", conflict)
+		}
+	}
+	r.reportedConflicts = len(conflicts)
+}
+
+// save writes the knowledge base to the storage, if any.
+func (r *repl) save() {
+	if r.storage == nil {
+		return
+	}
+
+	if err := r.storage.Save(r.parser.Knowledge()); err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+	}
+}
+
+func (r *repl) exportKnowledge(fileLoc string) {
+	file, err := os.Create(fileLoc)
+	if err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+		return
+	}
+
+	err = storages.Encode(file, r.parser.Knowledge())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+	}
+}
+
+func (r *repl) importKnowledge(fileLoc string) {
+	file, err := r.fileReader.Open(fileLoc)
+	if err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+		return
+	}
+	defer file.Close()
+
+	knowledge, err := storages.Decode(file)
+	if err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+		return
+	}
+
+	if err := r.parser.Restore(knowledge); err != nil {
+		fmt.Fprintf(r.output, "error: %s\n", err)
+		return
+	}
+
+	r.save()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
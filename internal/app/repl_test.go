@@ -0,0 +1,337 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/app"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	mockParser "github.com/arieffian/roman-alien-currency/internal/pkg/parsers/mocks"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	mockReader "github.com/arieffian/roman-alien-currency/internal/pkg/readers/mocks"
+	mockRenderer "github.com/arieffian/roman-alien-currency/internal/pkg/renderers/mocks"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
+	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
+)
+
+func TestRepl(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parser := mockParser.NewMockParserService(ctrl)
+	fileReader := mockReader.NewMockFileService(ctrl)
+	renderer := mockRenderer.NewMockRendererService(ctrl)
+
+	type args struct {
+		input string
+	}
+
+	type want struct {
+		output string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when question is typed should print the corrections and the answer",
+			args: args{
+				input: "How much is glb ?\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					FixTypo(parsers.Line{File: "repl", Number: 1, Text: "how much is glb ?", Raw: "How much is glb ?"}).
+					Return(parsers.Line{File: "repl", Number: 1, Text: "how much is glob ?", Raw: "How much is glb ?"}, []parsers.Correction{
+						{File: "repl", Line: 1, Column: 13, From: "glb", To: "glob", Confidence: 0.75},
+					})
+
+				parser.
+					EXPECT().
+					ParseCurrency(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+
+				parser.
+					EXPECT().
+					ParseMetal(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+
+				answer := parsers.Answer{
+					Line:     1,
+					Question: "how much is glob ?",
+					Kind:     parsers.QuestionKindHowMuch,
+					Value:    rationals.FromInt(1),
+				}
+
+				parser.
+					EXPECT().
+					ProcessQuestion(gomock.Any(), []parsers.Line{{File: "repl", Number: 1, Text: "how much is glob ?", Raw: "How much is glb ?"}}).
+					Return([]parsers.Answer{answer}, nil)
+
+				renderer.
+					EXPECT().
+					Render(answer).
+					Return("glob is 1")
+			},
+			want: want{
+				output: "> note: repl:1:13: corrected 'glb' to 'glob' (confidence 75%)\nglob is 1\n> \n",
+			},
+		},
+		{
+			name: "when definition is typed should print nothing",
+			args: args{
+				input: "glob is I\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					FixTypo(parsers.Line{File: "repl", Number: 1, Text: "glob is i", Raw: "glob is I"}).
+					Return(parsers.Line{File: "repl", Number: 1, Text: "glob is i", Raw: "glob is I"}, nil)
+
+				parser.
+					EXPECT().
+					ParseCurrency(gomock.Any(), parsers.Line{File: "repl", Number: 1, Text: "glob is i", Raw: "glob is I"}).
+					Return(true, nil)
+
+				parser.
+					EXPECT().
+					Conflicts().
+					Return(nil)
+			},
+			want: want{
+				output: "> > ",
+			},
+		},
+		{
+			name: "when definition conflicts should print the warning",
+			args: args{
+				input: "glob is v\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					FixTypo(gomock.Any()).
+					DoAndReturn(func(line parsers.Line) (parsers.Line, []parsers.Correction) {
+						return line, nil
+					})
+
+				parser.
+					EXPECT().
+					ParseCurrency(gomock.Any(), gomock.Any()).
+					Return(true, nil)
+
+				parser.
+					EXPECT().
+					Conflicts().
+					Return([]parsers.Conflict{
+						{
+							Kind:          parsers.ConflictRedefinedWord,
+							Name:          "glob",
+							Value:         "v",
+							PreviousValue: "i",
+							Previous:      parsers.Source{File: "definitions", Line: 1, Column: 1, Text: "glob is i"},
+							Current:       parsers.Source{File: "repl", Line: 1, Column: 1, Text: "glob is v"},
+							Policy:        parsers.ConflictWarn,
+						},
+					})
+			},
+			want: want{
+				output: "> warning: repl:1:1: 'glob' is redefined as 'v' by \"glob is v\", it was 'i' by definitions:1:1 \"glob is i\", the last definition is kept\n> ",
+			},
+		},
+		{
+			name: "when metal is invalid should print the error",
+			args: args{
+				input: "glob gold is 1a credits\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					FixTypo(gomock.Any()).
+					Return(parsers.Line{File: "repl", Number: 1, Text: "glob gold is 1a credits"}, nil)
+
+				parser.
+					EXPECT().
+					ParseCurrency(gomock.Any(), gomock.Any()).
+					Return(false, nil)
+
+				parser.
+					EXPECT().
+					ParseMetal(gomock.Any(), gomock.Any()).
+					Return(false, errors.New("error"))
+			},
+			want: want{
+				output: "> error: error\n> ",
+			},
+		},
+		{
+			name: "when dict command is typed should print the dictionary",
+			args: args{
+				input: ":dict\n:metals\n:rates\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					AlienDictionary().
+					Return(map[string]string{"prok": "v", "glob": "i"})
+
+				parser.
+					EXPECT().
+					MetalValue().
+					Return(map[string]rationals.Rational{"gold": rationals.FromInt(14450), "silver": rationals.New(52, 3)})
+
+				parser.
+					EXPECT().
+					Knowledge().
+					Return(storages.KnowledgeBase{
+						Rates:    []storages.Rate{{From: "credits", To: "zorbs", Rate: rationals.New(7, 3)}},
+						Dialects: map[string]map[string]string{"vega": {"zib": "x", "blip": "i"}},
+					}).
+					Times(2)
+			},
+			want: want{
+				output: "> glob is I\nprok is V\non vega blip is I\non vega zib is X\n> gold is 14450 Credits\nsilver is 17.3 Credits\n> 1 credits is 2.3 zorbs\n> ",
+			},
+		},
+		{
+			name: "when reset command is typed should reset the parser",
+			args: args{
+				input: ":reset\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				parser.
+					EXPECT().
+					Reset()
+			},
+			want: want{
+				output: "> > ",
+			},
+		},
+		{
+			name: "when load command fails should print the error",
+			args: args{
+				input: ":load Trades.txt\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				fileReader.
+					EXPECT().
+					Open("Trades.txt").
+					Return(nil, errors.New("error"))
+			},
+			want: want{
+				output: "> error: error\n> ",
+			},
+		},
+		{
+			name: "when import command is typed should restore the knowledge base",
+			args: args{
+				input: ":import knowledge.json\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				fileReader.
+					EXPECT().
+					Open("knowledge.json").
+					Return(io.NopCloser(strings.NewReader(`{"dictionary": {"glob": "i"}, "prices": {"gold": "28900/3"}}`)), nil)
+
+				parser.
+					EXPECT().
+					Restore(storages.KnowledgeBase{
+						Dictionary: map[string]string{"glob": "i"},
+						Prices:     map[string]rationals.Rational{"gold": rationals.New(28900, 3)},
+					}).
+					Return(nil)
+			},
+			want: want{
+				output: "> > ",
+			},
+		},
+		{
+			name: "when import file is invalid should print the error",
+			args: args{
+				input: ":import knowledge.json\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				fileReader.
+					EXPECT().
+					Open("knowledge.json").
+					Return(io.NopCloser(strings.NewReader(`{"prices": {"gold": "a lot"}}`)), nil)
+			},
+			want: want{
+				output: "> error: invalid knowledge base: invalid number 'a lot'\n> ",
+			},
+		},
+		{
+			name: "when command is unknown should print a hint",
+			args: args{
+				input: ":foo\n:quit\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "> unknown command :foo, type :help for the list of commands\n> ",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			output := &bytes.Buffer{}
+			repl, _ := app.NewRepl(app.NewReplParams{
+				Parser:     parser,
+				FileReader: fileReader,
+				Renderer:   renderer,
+				Input:      strings.NewReader(tc.args.input),
+				Output:     output,
+			})
+
+			err := repl.Run(context.Background())
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			if diff := deep.Equal(output.String(), tc.want.output); diff != nil {
+				t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", tc.want.output, output.String(), diff)
+			}
+		})
+	}
+}
+
+func TestReplCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parser := mockParser.NewMockParserService(ctrl)
+
+	// the input never gives a line, like a terminal nobody types in
+	input, writer := io.Pipe()
+	defer writer.Close()
+
+	output := &bytes.Buffer{}
+	repl, _ := app.NewRepl(app.NewReplParams{
+		Parser: parser,
+		Input:  input,
+		Output: output,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repl.Run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", context.Canceled, err)
+	}
+
+	if diff := deep.Equal(output.String(), "> \n"); diff != nil {
+		t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", "> \n", output.String(), diff)
+	}
+}
@@ -0,0 +1,434 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+)
+
+const sessionsPath = "/sessions"
+
+// defaultMaxBodySize caps the size of a request body, 1 MiB.
+const defaultMaxBodySize = 1 << 20
+
+var (
+	errSessionNotFound  = errors.New("session not found")
+	errMethodNotAllowed = errors.New("method not allowed")
+	errRouteNotFound    = errors.New("route not found")
+)
+
+// ParserFactory creates the parser used by a new session.
+type ParserFactory func(converter converters.ConverterService) parsers.ParserService
+
+type server struct {
+	converter   converters.ConverterService
+	renderer    renderers.RendererService
+	newParser   ParserFactory
+	rounding    rationals.Rounding
+	maxBodySize int64
+	mu          sync.RWMutex
+	sessions    map[string]*session
+}
+
+// session serializes every request on its parser since the parser state is not synchronized.
+type session struct {
+	mu     sync.Mutex
+	parser parsers.ParserService
+}
+
+type NewServerParams struct {
+	Converter converters.ConverterService
+	Renderer  renderers.RendererService
+	NewParser ParserFactory
+	// Rounding writes the credits and prices, rationals.DefaultRounding when its mode is not set.
+	Rounding rationals.Rounding
+	// MaxBodySize caps the size in bytes of a request body, 1 MiB when not set.
+	MaxBodySize int64
+}
+
+type sessionResponse struct {
+	ID string `json:"id"`
+}
+
+type statementsRequest struct {
+	Statements []string `json:"statements"`
+}
+
+type statementResult struct {
+	Statement   string   `json:"statement"`
+	Accepted    bool     `json:"accepted"`
+	Corrections []string `json:"corrections,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+type statementsResponse struct {
+	Statements []statementResult `json:"statements"`
+}
+
+type questionsRequest struct {
+	Questions []string `json:"questions"`
+}
+
+type operandResult struct {
+	Alien     string      `json:"alien"`
+	Value     int         `json:"value"`
+	Commodity string      `json:"commodity,omitempty"`
+	Credits   json.Number `json:"credits,omitempty"`
+}
+
+type questionResult struct {
+	Question      string          `json:"question"`
+	Answer        string          `json:"answer"`
+	Kind          string          `json:"kind"`
+	Operands      []operandResult `json:"operands,omitempty"`
+	Value         json.Number     `json:"value"`
+	Commodity     string          `json:"commodity,omitempty"`
+	Comparison    string          `json:"comparison,omitempty"`
+	Alien         string          `json:"alien,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	ErrorCategory string          `json:"error_category,omitempty"`
+	Corrections   []string        `json:"corrections,omitempty"`
+}
+
+type questionsResponse struct {
+	Answers []questionResult `json:"answers"`
+}
+
+type dictionaryResponse struct {
+	Dictionary map[string]string `json:"dictionary"`
+}
+
+type metalsResponse struct {
+	Metals map[string]json.Number `json:"metals"`
+}
+
+type conflictsResponse struct {
+	Conflicts []string `json:"conflicts"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func NewServer(p NewServerParams) (*server, error) {
+
+	rounding := p.Rounding
+	if rounding.Mode == "" {
+		rounding = rationals.DefaultRounding
+	}
+
+	newParser := p.NewParser
+	if newParser == nil {
+		newParser = func(converter converters.ConverterService) parsers.ParserService {
+			return parsers.NewParser(parsers.NewParserParams{
+				Converter:       converter,
+				AlienDictionary: map[string]string{},
+				MetalValue:      map[string]rationals.Rational{},
+				Rounding:        rounding,
+			})
+		}
+	}
+
+	maxBodySize := p.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+
+	return &server{
+		converter:   p.Converter,
+		renderer:    p.Renderer,
+		newParser:   newParser,
+		rounding:    rounding,
+		maxBodySize: maxBodySize,
+		sessions:    map[string]*session{},
+	}, nil
+}
+
+// ServeHTTP routes
+//
+//	POST   /sessions
+//	DELETE /sessions/{id}
+//	POST   /sessions/{id}/statements
+//	POST   /sessions/{id}/questions
+//	GET    /sessions/{id}/dictionary
+//	GET    /sessions/{id}/metals
+//	GET    /sessions/{id}/conflicts
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	segments := strings.Split(path, "/")
+
+	if segments[0] != strings.Trim(sessionsPath, "/") {
+		writeError(w, http.StatusNotFound, errRouteNotFound)
+		return
+	}
+
+	switch len(segments) {
+	case 1:
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errMethodNotAllowed)
+			return
+		}
+		s.createSession(w, r)
+	case 2:
+		if r.Method != http.MethodDelete {
+			writeError(w, http.StatusMethodNotAllowed, errMethodNotAllowed)
+			return
+		}
+		s.deleteSession(w, r, segments[1])
+	case 3:
+		sess, ok := s.session(segments[1])
+		if !ok {
+			writeError(w, http.StatusNotFound, errSessionNotFound)
+			return
+		}
+
+		s.routeSession(w, r, sess, segments[2])
+	default:
+		writeError(w, http.StatusNotFound, errRouteNotFound)
+	}
+}
+
+func (s *server) routeSession(w http.ResponseWriter, r *http.Request, sess *session, resource string) {
+	routes := map[string]struct {
+		method  string
+		handler func(http.ResponseWriter, *http.Request, *session)
+	}{
+		"statements": {http.MethodPost, s.addStatements},
+		"questions":  {http.MethodPost, s.askQuestions},
+		"dictionary": {http.MethodGet, s.getDictionary},
+		"metals":     {http.MethodGet, s.getMetals},
+		"conflicts":  {http.MethodGet, s.getConflicts},
+	}
+
+	route, ok := routes[resource]
+	if !ok {
+		writeError(w, http.StatusNotFound, errRouteNotFound)
+		return
+	}
+
+	if r.Method != route.method {
+		writeError(w, http.StatusMethodNotAllowed, errMethodNotAllowed)
+		return
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	route.handler(w, r, sess)
+}
+
+func (s *server) session(id string) (*session, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, ok := s.sessions[id]
+	return sess, ok
+}
+
+func (s *server) createSession(w http.ResponseWriter, r *http.Request) {
+	id, err := newSessionID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.sessions[id] = &session{parser: s.newParser(s.converter)}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, sessionResponse{ID: id})
+}
+
+func (s *server) deleteSession(w http.ResponseWriter, r *http.Request, id string) {
+	s.mu.Lock()
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, errSessionNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) addStatements(w http.ResponseWriter, r *http.Request, sess *session) {
+	var req statementsRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	res := statementsResponse{Statements: []statementResult{}}
+	for idx, statement := range req.Statements {
+		// nobody is waiting for the response of a cancelled request
+		if r.Context().Err() != nil {
+			return
+		}
+
+		line, corrections := sess.parser.FixTypo(parsers.Line{
+			Number: idx + 1,
+			Text:   readers.NormalizeLine(statement),
+			Raw:    statement,
+		})
+
+		result := statementResult{Statement: statement, Corrections: correctionStrings(corrections)}
+		found, err := sess.parser.ParseCurrency(r.Context(), line)
+		if err == nil && !found {
+			found, err = sess.parser.ParseMetal(r.Context(), line)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		result.Accepted = found
+
+		res.Statements = append(res.Statements, result)
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *server) askQuestions(w http.ResponseWriter, r *http.Request, sess *session) {
+	var req questionsRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	lines := make([]parsers.Line, 0, len(req.Questions))
+	corrections := make([][]parsers.Correction, 0, len(req.Questions))
+	for idx, question := range req.Questions {
+		line, lineCorrections := sess.parser.FixTypo(parsers.Line{
+			Number: idx + 1,
+			Text:   readers.NormalizeLine(question),
+			Raw:    question,
+		})
+		lines = append(lines, line)
+		corrections = append(corrections, lineCorrections)
+	}
+
+	answers, err := sess.parser.ProcessQuestion(r.Context(), lines)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	res := questionsResponse{Answers: []questionResult{}}
+	for _, answer := range answers {
+		result := s.questionResult(req.Questions[answer.Line-1], answer)
+		result.Corrections = correctionStrings(corrections[answer.Line-1])
+		res.Answers = append(res.Answers, result)
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *server) questionResult(question string, answer parsers.Answer) questionResult {
+	result := questionResult{
+		Question:      question,
+		Answer:        s.renderer.Render(answer),
+		Kind:          string(answer.Kind),
+		Value:         json.Number(s.rounding.Format(answer.Value)),
+		Commodity:     answer.Commodity,
+		Comparison:    string(answer.Comparison),
+		Alien:         strings.Join(answer.Alien, " "),
+		ErrorCategory: string(answer.Category),
+	}
+
+	if answer.Err != nil {
+		result.Error = answer.Err.Error()
+	}
+
+	for _, operand := range answer.Operands {
+		operandResult := operandResult{
+			Alien:     strings.Join(operand.Alien, " "),
+			Value:     operand.Value,
+			Commodity: operand.Commodity,
+		}
+		if operand.Credits.Sign() != 0 {
+			operandResult.Credits = json.Number(s.rounding.Format(operand.Credits))
+		}
+
+		result.Operands = append(result.Operands, operandResult)
+	}
+
+	return result
+}
+
+func correctionStrings(corrections []parsers.Correction) []string {
+	var result []string
+	for _, correction := range corrections {
+		result = append(result, correction.String())
+	}
+
+	return result
+}
+
+func (s *server) getDictionary(w http.ResponseWriter, r *http.Request, sess *session) {
+	writeJSON(w, http.StatusOK, dictionaryResponse{Dictionary: sess.parser.AlienDictionary()})
+}
+
+func (s *server) getMetals(w http.ResponseWriter, r *http.Request, sess *session) {
+	metals := map[string]json.Number{}
+	for metal, value := range sess.parser.MetalValue() {
+		metals[metal] = json.Number(s.rounding.Format(value))
+	}
+
+	writeJSON(w, http.StatusOK, metalsResponse{Metals: metals})
+}
+
+func (s *server) getConflicts(w http.ResponseWriter, r *http.Request, sess *session) {
+	res := conflictsResponse{Conflicts: []string{}}
+	for _, conflict := range sess.parser.Conflicts() {
+		res.Conflicts = append(res.Conflicts, conflict.String())
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func newSessionID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// decodeRequest decodes the JSON body of r into req, it writes the error
+// response and returns false when the body is invalid or too large.
+func (s *server) decodeRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBodySize)).Decode(req)
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return false
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,330 @@
+package app_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/app"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+	"github.com/go-test/deep"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	server, _ := app.NewServer(app.NewServerParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+		Renderer:  renderers.NewText(renderers.NewTextParams{}),
+	})
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func doRequest(t *testing.T, method string, url string, body string, result interface{}) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Errorf("failed to create request: %v", err)
+		return 0
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("failed to do request: %v", err)
+		return 0
+	}
+	defer res.Body.Close()
+
+	if result != nil {
+		err = json.NewDecoder(res.Body).Decode(result)
+		if err != nil {
+			t.Errorf("failed to decode response: %v", err)
+		}
+	}
+
+	return res.StatusCode
+}
+
+func createSession(t *testing.T, ts *httptest.Server) string {
+	var session struct {
+		ID string `json:"id"`
+	}
+
+	status := doRequest(t, http.MethodPost, ts.URL+"/sessions", "", &session)
+	if status != http.StatusCreated {
+		t.Fatalf("got unexpected status creating session: %d", status)
+	}
+
+	return session.ID
+}
+
+func TestServer(t *testing.T) {
+	ts := newTestServer(t)
+	id := createSession(t, ts)
+	sessionURL := ts.URL + "/sessions/" + id
+
+	type args struct {
+		method string
+		url    string
+		body   string
+	}
+
+	type want struct {
+		status int
+		body   map[string]interface{}
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when statements are valid should learn them",
+			args: args{
+				method: http.MethodPost,
+				url:    sessionURL + "/statements",
+				body:   `{"statements": ["glob is I", "prok is V", "glob prok Gold is 57800 Credits", "how much is glob ?"]}`,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				status: http.StatusOK,
+				body: map[string]interface{}{
+					"statements": []interface{}{
+						map[string]interface{}{"statement": "glob is I", "accepted": true},
+						map[string]interface{}{"statement": "prok is V", "accepted": true},
+						map[string]interface{}{"statement": "glob prok Gold is 57800 Credits", "accepted": true},
+						map[string]interface{}{"statement": "how much is glob ?", "accepted": false},
+					},
+				},
+			},
+		},
+		{
+			name: "when questions are asked should answer them",
+			args: args{
+				method: http.MethodPost,
+				url:    sessionURL + "/questions",
+				body:   `{"questions": ["how much is glob prok ?", "how many Credits is glob glob Gold ?", "how much wood ?"]}`,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				status: http.StatusOK,
+				body: map[string]interface{}{
+					"answers": []interface{}{
+						map[string]interface{}{
+							"question": "how much is glob prok ?",
+							"answer":   "glob prok is 4",
+							"kind":     "how_much",
+							"operands": []interface{}{
+								map[string]interface{}{"alien": "glob prok", "value": float64(4)},
+							},
+							"value": float64(4),
+						},
+						map[string]interface{}{
+							"question": "how many Credits is glob glob Gold ?",
+							"answer":   "glob glob gold is 28900 Credits",
+							"kind":     "how_many",
+							"operands": []interface{}{
+								map[string]interface{}{"alien": "glob glob", "value": float64(2), "commodity": "gold", "credits": float64(28900)},
+							},
+							"value":     float64(28900),
+							"commodity": "gold",
+						},
+						map[string]interface{}{
+							"question":       "how much wood ?",
+							"answer":         "I have no idea what you are talking about",
+							"kind":           "how_much",
+							"value":          float64(0),
+							"error":          "3:10: expected 'is', found 'wood'",
+							"error_category": "malformed_question",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "when dictionary is requested should return it",
+			args: args{
+				method: http.MethodGet,
+				url:    sessionURL + "/dictionary",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				status: http.StatusOK,
+				body: map[string]interface{}{
+					"dictionary": map[string]interface{}{"glob": "i", "prok": "v"},
+				},
+			},
+		},
+		{
+			name: "when metals are requested should return them",
+			args: args{
+				method: http.MethodGet,
+				url:    sessionURL + "/metals",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				status: http.StatusOK,
+				body: map[string]interface{}{
+					"metals": map[string]interface{}{"gold": float64(14450)},
+				},
+			},
+		},
+		{
+			name: "when conflicts are requested should return them",
+			args: args{
+				method: http.MethodGet,
+				url:    sessionURL + "/conflicts",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				status: http.StatusOK,
+				body: map[string]interface{}{
+					"conflicts": []interface{}{},
+				},
+			},
+		},
+		{
+			name: "when session is unknown should return not found",
+			args: args{
+				method: http.MethodGet,
+				url:    ts.URL + "/sessions/unknown/dictionary",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				status: http.StatusNotFound,
+				body:   map[string]interface{}{"error": "session not found"},
+			},
+		},
+		{
+			name: "when method is not allowed should return error",
+			args: args{
+				method: http.MethodGet,
+				url:    sessionURL + "/questions",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				status: http.StatusMethodNotAllowed,
+				body:   map[string]interface{}{"error": "method not allowed"},
+			},
+		},
+		{
+			name: "when body is invalid should return bad request",
+			args: args{
+				method: http.MethodPost,
+				url:    sessionURL + "/questions",
+				body:   `{"questions": `,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				status: http.StatusBadRequest,
+				body:   map[string]interface{}{"error": "unexpected EOF"},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			var body map[string]interface{}
+			status := doRequest(t, tc.args.method, tc.args.url, tc.args.body, &body)
+
+			if diff := deep.Equal(status, tc.want.status); diff != nil {
+				t.Errorf("got unexpected status.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.status, status, diff)
+			}
+
+			if diff := deep.Equal(body, tc.want.body); diff != nil {
+				t.Errorf("got unexpected body.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.body, body, diff)
+			}
+		})
+	}
+}
+
+func TestServerBodyTooLarge(t *testing.T) {
+	server, _ := app.NewServer(app.NewServerParams{
+		Converter:   converters.NewConverter(converters.NewConverterParams{}),
+		Renderer:    renderers.NewText(renderers.NewTextParams{}),
+		MaxBodySize: 32,
+	})
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	id := createSession(t, ts)
+
+	var body map[string]interface{}
+	status := doRequest(t, http.MethodPost, ts.URL+"/sessions/"+id+"/statements", `{"statements": ["glob is I", "prok is V", "pish is X"]}`, &body)
+
+	if diff := deep.Equal(status, http.StatusRequestEntityTooLarge); diff != nil {
+		t.Errorf("got unexpected status.\n expected: %v\n actual: %v\n diff: %v\n", http.StatusRequestEntityTooLarge, status, diff)
+	}
+
+	expected := map[string]interface{}{"error": "http: request body too large"}
+	if diff := deep.Equal(body, expected); diff != nil {
+		t.Errorf("got unexpected body.\n expected: %v\n actual: %v\n diff: %v\n", expected, body, diff)
+	}
+}
+
+func TestServerSessionsAreIsolated(t *testing.T) {
+	ts := newTestServer(t)
+	first := createSession(t, ts)
+	second := createSession(t, ts)
+
+	doRequest(t, http.MethodPost, ts.URL+"/sessions/"+first+"/statements", `{"statements": ["glob is I"]}`, nil)
+
+	var body struct {
+		Dictionary map[string]string `json:"dictionary"`
+	}
+	doRequest(t, http.MethodGet, ts.URL+"/sessions/"+second+"/dictionary", "", &body)
+
+	if diff := deep.Equal(body.Dictionary, map[string]string{}); diff != nil {
+		t.Errorf("got unexpected dictionary.\n diff: %v\n", diff)
+	}
+
+	status := doRequest(t, http.MethodDelete, ts.URL+"/sessions/"+second, "", nil)
+	if diff := deep.Equal(status, http.StatusNoContent); diff != nil {
+		t.Errorf("got unexpected status.\n diff: %v\n", diff)
+	}
+}
+
+func TestServerConcurrentRequests(t *testing.T) {
+	ts := newTestServer(t)
+	id := createSession(t, ts)
+	sessionURL := ts.URL + "/sessions/" + id
+
+	words := []string{"glob", "prok", "pish", "tegj"}
+	romans := []string{"I", "V", "X", "L"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"statements": ["%s is %s"]}`, words[i%4], romans[i%4])
+			doRequest(t, http.MethodPost, sessionURL+"/statements", body, nil)
+		}(i)
+		go func() {
+			defer wg.Done()
+			doRequest(t, http.MethodPost, sessionURL+"/questions", `{"questions": ["how much is glob ?"]}`, nil)
+		}()
+	}
+	wg.Wait()
+
+	var body struct {
+		Dictionary map[string]string `json:"dictionary"`
+	}
+	doRequest(t, http.MethodGet, sessionURL+"/dictionary", "", &body)
+
+	if diff := deep.Equal(len(body.Dictionary), len(words)); diff != nil {
+		t.Errorf("got unexpected dictionary.\n diff: %v\n", diff)
+	}
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+)
+
+const (
+	// inputExtension and outputExtension name the files of a golden case.
+	inputExtension  = ".in"
+	outputExtension = ".out"
+	// diffContext is the number of unchanged lines around every change of a diff.
+	diffContext = 3
+)
+
+var (
+	ErrNoGoldenCase = errors.New("no golden case")
+	ErrMismatch     = errors.New("golden cases failed")
+)
+
+type verifier struct {
+	converter  converters.ConverterService
+	newParser  ParserFactory
+	fileReader readers.FileService
+	renderer   renderers.RendererService
+	dir        string
+	output     io.Writer
+	hoist      bool
+	format     renderers.Format
+	rounding   rationals.Rounding
+}
+
+type NewVerifierParams struct {
+	Converter converters.ConverterService
+	// NewParser creates the parser of every case, so that cases never share their definitions.
+	NewParser  ParserFactory
+	FileReader readers.FileService
+	Renderer   renderers.RendererService
+	// Dir holds the cases, every name.in input is expected to print name.out.
+	Dir string
+	// Output receives the result of every case along with the diff of the failed ones.
+	Output io.Writer
+	// Hoist, Format and Rounding are passed on to the cli running the cases.
+	Hoist    bool
+	Format   renderers.Format
+	Rounding rationals.Rounding
+}
+
+func NewVerifier(p NewVerifierParams) (*verifier, error) {
+
+	output := p.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	return &verifier{
+		converter:  p.Converter,
+		newParser:  p.NewParser,
+		fileReader: p.FileReader,
+		renderer:   p.Renderer,
+		dir:        p.Dir,
+		output:     output,
+		hoist:      p.Hoist,
+		format:     p.Format,
+		rounding:   p.Rounding,
+	}, nil
+}
+
+// Run runs every case in name order and returns ErrMismatch when any of them
+// failed, it stops once ctx is done.
+func (v *verifier) Run(ctx context.Context) error {
+
+	inputs, err := filepath.Glob(filepath.Join(v.dir, "*"+inputExtension))
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("%w in '%s'", ErrNoGoldenCase, v.dir)
+	}
+	sort.Strings(inputs)
+
+	failed := 0
+	for _, input := range inputs {
+		passed, err := v.runCase(ctx, input)
+		if err != nil {
+			return err
+		}
+		if !passed {
+			failed++
+		}
+	}
+
+	fmt.Fprintf(v.output, "%d passed, %d failed\n", len(inputs)-failed, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%w, %d of %d", ErrMismatch, failed, len(inputs))
+	}
+
+	return nil
+}
+
+// runCase tells whether input prints its expected output, a case that cannot
+// run fails with the reason instead of a diff.
+func (v *verifier) runCase(ctx context.Context, input string) (bool, error) {
+	name := strings.TrimSuffix(filepath.Base(input), inputExtension)
+	expectedFile := strings.TrimSuffix(input, inputExtension) + outputExtension
+
+	expected, err := v.readExpected(expectedFile)
+	if err != nil {
+		fmt.Fprintf(v.output, "FAIL %s: %s\n", name, err)
+		return false, nil
+	}
+
+	actual := &bytes.Buffer{}
+	cli, err := NewCli(NewCliParams{
+		Converter:  v.converter,
+		Parser:     v.newParser(v.converter),
+		FileReader: v.fileReader,
+		Renderer:   v.renderer,
+		Inputs:     []string{input},
+		Output:     actual,
+		Hoist:      v.hoist,
+		Format:     v.format,
+		Rounding:   v.rounding,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := cli.Run(ctx); err != nil {
+		// the remaining cases cannot run either
+		if ctx.Err() != nil {
+			return false, err
+		}
+
+		fmt.Fprintf(v.output, "FAIL %s: %s\n", name, err)
+		return false, nil
+	}
+
+	// a missing newline at the end of the expected output is not a difference
+	if strings.TrimSuffix(actual.String(), "\n") == strings.TrimSuffix(expected, "\n") {
+		fmt.Fprintf(v.output, "PASS %s\n", name)
+		return true, nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(expected),
+		B:        diffLines(actual.String()),
+		FromFile: expectedFile,
+		ToFile:   "actual",
+		Context:  diffContext,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(v.output, "FAIL %s\n%s", name, diff)
+
+	return false, nil
+}
+
+func (v *verifier) readExpected(file string) (string, error) {
+	reader, err := v.fileReader.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	expected, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(string(expected), "\r\n", "\n"), nil
+}
+
+// diffLines splits text into lines ending with a newline, the last one included.
+func diffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return difflib.SplitLines(strings.TrimSuffix(text, "\n"))
+}
@@ -0,0 +1,165 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/app"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+	"github.com/go-test/deep"
+)
+
+func TestVerifier(t *testing.T) {
+
+	newParser := func(converter converters.ConverterService) parsers.ParserService {
+		return parsers.NewParser(parsers.NewParserParams{
+			Converter:       converter,
+			AlienDictionary: map[string]string{},
+			MetalValue:      map[string]rationals.Rational{},
+		})
+	}
+
+	writeCases := func(t *testing.T, cases map[string]string) string {
+		dir := t.TempDir()
+		for name, content := range cases {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+		}
+		return dir
+	}
+
+	type args struct {
+		cases map[string]string
+	}
+
+	type want struct {
+		output string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when every case prints its expected output should pass",
+			args: args{
+				cases: map[string]string{
+					"glob.in":  "glob is I\nhow much is glob glob ?\n",
+					"glob.out": "glob glob is 2\n",
+					"say.in":   "glob is I\nhow do you say 3 ?\n",
+					// the last newline is optional
+					"say.out": "3 is glob glob glob",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "PASS glob\nPASS say\n2 passed, 0 failed\n",
+			},
+		},
+		{
+			name: "when cases share a word should not share the definitions",
+			args: args{
+				cases: map[string]string{
+					"a.in":  "glob is V\n",
+					"a.out": "",
+					"b.in":  "how much is glob ?\n",
+					"b.out": "Requested number contains unknown words\n",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "PASS a\nPASS b\n2 passed, 0 failed\n",
+			},
+		},
+		{
+			name: "when a case prints another output should report a unified diff",
+			args: args{
+				cases: map[string]string{
+					"glob.in":  "glob is I\nhow much is glob ?\nhow much is glob glob ?\n",
+					"glob.out": "glob is 1\nglob glob is 3\n",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "FAIL glob\n--- {dir}/glob.out\n+++ actual\n@@ -1,2 +1,2 @@\n glob is 1\n-glob glob is 3\n+glob glob is 2\n0 passed, 1 failed\n",
+				error:  errors.New("golden cases failed, 1 of 1"),
+			},
+		},
+		{
+			name: "when the expected output is missing should fail the case",
+			args: args{
+				cases: map[string]string{
+					"glob.in": "glob is I\n",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "FAIL glob: open {dir}/glob.out: no such file or directory\n0 passed, 1 failed\n",
+				error:  errors.New("golden cases failed, 1 of 1"),
+			},
+		},
+		{
+			name: "when there is no case should return error",
+			args: args{
+				cases: map[string]string{
+					"glob.out": "glob is 1\n",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				output: "",
+				error:  errors.New("no golden case in '{dir}'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			dir := writeCases(t, tc.args.cases)
+			output := &bytes.Buffer{}
+
+			verifier, _ := app.NewVerifier(app.NewVerifierParams{
+				Converter:  converters.NewConverter(converters.NewConverterParams{}),
+				NewParser:  newParser,
+				FileReader: readers.NewFile(),
+				Renderer:   renderers.NewText(renderers.NewTextParams{}),
+				Dir:        dir,
+				Output:     output,
+			})
+
+			err := verifier.Run(context.Background())
+
+			if tc.want.error == nil && err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if tc.want.error != nil {
+				expected := strings.ReplaceAll(tc.want.error.Error(), "{dir}", dir)
+				if err == nil || err.Error() != expected {
+					t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", expected, err)
+				}
+			}
+
+			expected := strings.ReplaceAll(tc.want.output, "{dir}", dir)
+			if diff := deep.Equal(output.String(), expected); diff != nil {
+				t.Errorf("got unexpected output.\n expected: %q\n actual: %q\n diff: %v\n", expected, output.String(), diff)
+			}
+		})
+
+	}
+}
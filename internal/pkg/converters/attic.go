@@ -0,0 +1,87 @@
+package converters
+
+import (
+	"fmt"
+	"strings"
+)
+
+type attic struct{}
+
+var _ NumeralSystem = (*attic)(nil)
+
+const maxAttic = 99999
+
+// atticNumerals are the acrophonic numerals of Attica from the largest, a
+// number adds them up and writes each one at most max times.
+var atticNumerals = []struct {
+	val int
+	sym string
+	max int
+}{
+	{50000, "𐅇", 1},
+	{10000, "Μ", 4},
+	{5000, "𐅆", 1},
+	{1000, "Χ", 4},
+	{500, "𐅅", 1},
+	{100, "Η", 4},
+	{50, "𐅄", 1},
+	{10, "Δ", 4},
+	{5, "Π", 1},
+	{1, "Ι", 4},
+}
+
+// NewAttic returns the greek attic numeral system, 𐅄ΔΔΠΙΙ is 77.
+func NewAttic() *attic {
+	return &attic{}
+}
+
+func (a *attic) Name() string {
+	return "attic"
+}
+
+func (a *attic) Parse(number string) (int, error) {
+	rest := number
+
+	result := 0
+	for _, numeral := range atticNumerals {
+		for count := 0; count < numeral.max && strings.HasPrefix(rest, numeral.sym); count++ {
+			result += numeral.val
+			rest = rest[len(numeral.sym):]
+		}
+	}
+
+	if number == "" || rest != "" {
+		return 0, fmt.Errorf("%w '%s'", ErrInvalidNumber, number)
+	}
+
+	return result, nil
+}
+
+func (a *attic) Format(value int) (string, error) {
+	if value < 1 || value > maxAttic {
+		return "", fmt.Errorf("%w '%d'", ErrOutOfRange, value)
+	}
+
+	var result strings.Builder
+	for _, numeral := range atticNumerals {
+		for ; value >= numeral.val; value -= numeral.val {
+			result.WriteString(numeral.sym)
+		}
+	}
+
+	return result.String(), nil
+}
+
+func (a *attic) IsSymbol(symbol string) bool {
+	for _, numeral := range atticNumerals {
+		if symbol == numeral.sym {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *attic) Range() (int, int) {
+	return 1, maxAttic
+}
@@ -0,0 +1,93 @@
+package converters
+
+import (
+	"fmt"
+	"strings"
+)
+
+type babylonian struct{}
+
+var _ NumeralSystem = (*babylonian)(nil)
+
+const (
+	babylonianOne       = "𒁹"
+	babylonianTen       = "𒌋"
+	babylonianSeparator = "."
+	babylonianBase      = 60
+	babylonianPlaces    = 4
+	maxBabylonian       = 12959999
+)
+
+// NewBabylonian returns the babylonian sexagesimal numeral system. Every
+// place is a digit from 0 to 59 written with up to five 𒌋 followed by up to
+// nine 𒁹, places are separated by a dot and an empty place is zero, so
+// 𒌋𒁹.𒁹𒁹 is 11 * 60 + 2.
+func NewBabylonian() *babylonian {
+	return &babylonian{}
+}
+
+func (b *babylonian) Name() string {
+	return "babylonian"
+}
+
+func (b *babylonian) Parse(number string) (int, error) {
+	places := strings.Split(number, babylonianSeparator)
+	if places[0] == "" || len(places) > babylonianPlaces {
+		return 0, fmt.Errorf("%w '%s'", ErrInvalidNumber, number)
+	}
+
+	result := 0
+	for _, place := range places {
+		digit, ok := babylonianDigit(place)
+		if !ok {
+			return 0, fmt.Errorf("%w '%s'", ErrInvalidNumber, number)
+		}
+
+		result = result*babylonianBase + digit
+	}
+
+	return result, nil
+}
+
+func babylonianDigit(place string) (int, bool) {
+	tens := 0
+	for strings.HasPrefix(place, babylonianTen) {
+		tens++
+		place = strings.TrimPrefix(place, babylonianTen)
+	}
+
+	ones := 0
+	for strings.HasPrefix(place, babylonianOne) {
+		ones++
+		place = strings.TrimPrefix(place, babylonianOne)
+	}
+
+	if place != "" || tens > 5 || ones > 9 {
+		return 0, false
+	}
+
+	return tens*10 + ones, true
+}
+
+func (b *babylonian) Format(value int) (string, error) {
+	if value < 1 || value > maxBabylonian {
+		return "", fmt.Errorf("%w '%d'", ErrOutOfRange, value)
+	}
+
+	places := []string{}
+	for ; value > 0; value /= babylonianBase {
+		digit := value % babylonianBase
+		place := strings.Repeat(babylonianTen, digit/10) + strings.Repeat(babylonianOne, digit%10)
+		places = append([]string{place}, places...)
+	}
+
+	return strings.Join(places, babylonianSeparator), nil
+}
+
+func (b *babylonian) IsSymbol(symbol string) bool {
+	return symbol == babylonianOne || symbol == babylonianTen || symbol == babylonianSeparator
+}
+
+func (b *babylonian) Range() (int, int) {
+	return 1, maxBabylonian
+}
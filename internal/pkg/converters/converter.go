@@ -3,17 +3,48 @@ package converters
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 type ConverterService interface {
 	RomanToArabic(romanNumber string) (int, error)
 	ArabicToRoman(number int) (string, error)
 	AlienToRoman(alienDictionary map[string]string, alienNumber []string) (string, error)
+	RomanToAlien(alienDictionary map[string]string, romanNumber string) ([]string, error)
+	Range() (int, int)
 }
 
-type converter struct{}
+// Notation is the way numbers from 4000 and up are written.
+type Notation string
+
+const (
+	// NotationClassic writes numbers from 1 to 3999 with I, V, X, L, C, D and M.
+	NotationClassic Notation = "classic"
+	// NotationVinculum multiplies the symbols written with an overline by 1000,
+	// as in V̅MMXXI for 7021, up to 3999999.
+	NotationVinculum Notation = "vinculum"
+	// NotationApostrophus writes the thousands with CIↃ (1000), IↃↃ (5000),
+	// CCIↃↃ (10000), IↃↃↃ (50000) and CCCIↃↃↃ (100000) following the rules of
+	// I, V, X, L and C, as in CIↃIↃↃXXI for 4021, up to 399999.
+	NotationApostrophus Notation = "apostrophus"
+)
+
+const (
+	maxClassic     = 3999
+	maxVinculum    = 3999999
+	maxApostrophus = 399999
+
+	// overline is the combining character marking a vinculum symbol.
+	overline = '\u0305'
+)
+
+type converter struct {
+	notation Notation
+}
 
 type numeral struct {
 	val int
@@ -22,12 +53,38 @@ type numeral struct {
 
 var _ ConverterService = (*converter)(nil)
 
+var (
+	ErrInvalidRoman     = errors.New("invalid roman number")
+	ErrInvalidNumber    = errors.New("invalid number")
+	ErrOutOfRange       = errors.New("number out of range")
+	ErrUnknownAlienWord = errors.New("unknown alien word")
+	ErrNoAlienWord      = errors.New("no alien word")
+	ErrUnknownNotation  = errors.New("unknown notation")
+)
+
 var (
 	m0 = []string{"", "I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX"}
 	m1 = []string{"", "X", "XX", "XXX", "XL", "L", "LX", "LXX", "LXXX", "XC"}
 	m2 = []string{"", "C", "CC", "CCC", "CD", "D", "DC", "DCC", "DCCC", "CM"}
 	m3 = []string{"", "M", "MM", "MMM"}
 
+	classicRegex = regexp.MustCompile(`^M{0,3}(CM|CD|D?C{0,3})(XC|XL|L?X{0,3})(IX|IV|V?I{0,3})$`)
+	// hundredsRegex validates the part of an extended number written below the thousands.
+	hundredsRegex = regexp.MustCompile(`^(CM|CD|D?C{0,3})(XC|XL|L?X{0,3})(IX|IV|V?I{0,3})$`)
+
+	// apostrophi are the thousands of NotationApostrophus along with the
+	// classic symbol they stand for, the longest first.
+	apostrophi = []struct {
+		sym     string
+		classic string
+	}{
+		{"CCCIↃↃↃ", "C"},
+		{"CCIↃↃ", "X"},
+		{"IↃↃↃ", "L"},
+		{"IↃↃ", "V"},
+		{"CIↃ", "I"},
+	}
+
 	nums = []numeral{
 		{1000, []byte("M")},
 		{900, []byte("CM")},
@@ -45,19 +102,181 @@ var (
 	}
 )
 
-func NewConverter() *converter {
-	return &converter{}
+type NewConverterParams struct {
+	// Notation defaults to NotationClassic, extended notations still read classic numbers.
+	Notation Notation
+}
+
+func NewConverter(p NewConverterParams) *converter {
+	notation := p.Notation
+	if notation == "" {
+		notation = NotationClassic
+	}
+
+	return &converter{
+		notation: notation,
+	}
+}
+
+// ParseNotation returns the notation named name.
+func ParseNotation(name string) (Notation, error) {
+	switch notation := Notation(name); notation {
+	case NotationClassic, NotationVinculum, NotationApostrophus:
+		return notation, nil
+	default:
+		return "", fmt.Errorf("%w '%s'", ErrUnknownNotation, name)
+	}
 }
 
 // @note: converter based on https://github.com/brandenc40/romannumeral/blob/1823dc2593cc5ada13c3d9e8f941b1170ddcda29/romannumeral.go#L98
 func (c *converter) RomanToArabic(romanNumber string) (int, error) {
 
-	// validate roman number using regex
-	regex := regexp.MustCompile(`^M{0,3}(CM|CD|D?C{0,3})(XC|XL|L?X{0,3})(IX|IV|V?I{0,3})$`)
-	if !regex.MatchString(romanNumber) {
-		return 0, errors.New("invalid roman number")
+	var result int
+	var ok bool
+	switch c.notation {
+	case NotationVinculum:
+		result, ok = vinculumToArabic(romanNumber)
+	case NotationApostrophus:
+		result, ok = apostrophusToArabic(romanNumber)
+	default:
+		result, ok = classicToArabic(romanNumber)
+	}
+
+	if !ok {
+		return 0, fmt.Errorf("%w '%s'", ErrInvalidRoman, romanNumber)
+	}
+
+	return result, nil
+}
+
+// @note: converter based on https://github.com/brandenc40/romannumeral/blob/1823dc2593cc5ada13c3d9e8f941b1170ddcda29/romannumeral.go#L72
+func (c *converter) ArabicToRoman(arabicNumber int) (string, error) {
+	if arabicNumber < 1 || arabicNumber > c.maxNumber() {
+		return "", fmt.Errorf("%w '%d'", ErrOutOfRange, arabicNumber)
+	}
+
+	switch {
+	case c.notation == NotationVinculum && arabicNumber > maxClassic:
+		return withOverline(arabicToClassic(arabicNumber/1000)) + arabicToClassic(arabicNumber%1000), nil
+	case c.notation == NotationApostrophus && arabicNumber >= 1000:
+		return withApostrophi(arabicToClassic(arabicNumber/1000)) + arabicToClassic(arabicNumber%1000), nil
+	default:
+		return arabicToClassic(arabicNumber), nil
+	}
+}
+
+// Range returns the smallest and the largest number of the notation.
+func (c *converter) Range() (int, int) {
+	return 1, c.maxNumber()
+}
+
+func (c *converter) maxNumber() int {
+	switch c.notation {
+	case NotationVinculum:
+		return maxVinculum
+	case NotationApostrophus:
+		return maxApostrophus
+	default:
+		return maxClassic
+	}
+}
+
+func arabicToClassic(arabicNumber int) string {
+	return m3[arabicNumber%10000/1000] + m2[arabicNumber%1000/100] + m1[arabicNumber%100/10] + m0[arabicNumber%10]
+}
+
+func classicToArabic(romanNumber string) (int, bool) {
+	if romanNumber == "" || !classicRegex.MatchString(romanNumber) {
+		return 0, false
 	}
 
+	return sumNumerals(romanNumber), true
+}
+
+// vinculumToArabic reads the leading symbols written with an overline as
+// thousands, they must be worth at least 4 so that every number has a single form.
+func vinculumToArabic(romanNumber string) (int, bool) {
+	runes := []rune(romanNumber)
+
+	thousands := []rune{}
+	idx := 0
+	for idx+1 < len(runes) && runes[idx+1] == overline {
+		thousands = append(thousands, runes[idx])
+		idx += 2
+	}
+
+	if len(thousands) == 0 {
+		return classicToArabic(romanNumber)
+	}
+
+	high, ok := classicToArabic(string(thousands))
+	if !ok || high < 4 {
+		return 0, false
+	}
+
+	rest := string(runes[idx:])
+	if !hundredsRegex.MatchString(rest) {
+		return 0, false
+	}
+
+	return high*1000 + sumNumerals(rest), true
+}
+
+// apostrophusToArabic reads the leading apostrophus symbols as the classic
+// number of thousands they stand for.
+func apostrophusToArabic(romanNumber string) (int, bool) {
+	thousands := ""
+	rest := romanNumber
+
+	for found := true; found; {
+		found = false
+		for _, apostrophus := range apostrophi {
+			if strings.HasPrefix(rest, apostrophus.sym) {
+				thousands += apostrophus.classic
+				rest = strings.TrimPrefix(rest, apostrophus.sym)
+				found = true
+				break
+			}
+		}
+	}
+
+	if thousands == "" {
+		return classicToArabic(romanNumber)
+	}
+
+	high, ok := classicToArabic(thousands)
+	if !ok || !hundredsRegex.MatchString(rest) {
+		return 0, false
+	}
+
+	return high*1000 + sumNumerals(rest), true
+}
+
+func withOverline(romanNumber string) string {
+	var result strings.Builder
+	for _, r := range romanNumber {
+		result.WriteRune(r)
+		result.WriteRune(overline)
+	}
+
+	return result.String()
+}
+
+func withApostrophi(romanNumber string) string {
+	var result strings.Builder
+	for _, r := range romanNumber {
+		for _, apostrophus := range apostrophi {
+			if apostrophus.classic == string(r) {
+				result.WriteString(apostrophus.sym)
+			}
+		}
+	}
+
+	return result.String()
+}
+
+// sumNumerals adds up the values of a valid classic number.
+func sumNumerals(romanNumber string) int {
 	input := []byte(romanNumber)
 
 	var output int
@@ -68,18 +287,7 @@ func (c *converter) RomanToArabic(romanNumber string) (int, error) {
 		}
 	}
 
-	return output, nil
-}
-
-// @note: converter based on https://github.com/brandenc40/romannumeral/blob/1823dc2593cc5ada13c3d9e8f941b1170ddcda29/romannumeral.go#L72
-func (c *converter) ArabicToRoman(arabicNumber int) (string, error) {
-	if arabicNumber < 1 || arabicNumber >= 3999 {
-		return "", errors.New("number out of range")
-	}
-
-	result := m3[arabicNumber%10000/1000] + m2[arabicNumber%1000/100] + m1[arabicNumber%100/10] + m0[arabicNumber%10]
-
-	return result, nil
+	return output
 }
 
 func (c *converter) AlienToRoman(alienDictionary map[string]string, alienNumber []string) (string, error) {
@@ -88,7 +296,7 @@ func (c *converter) AlienToRoman(alienDictionary map[string]string, alienNumber
 	for _, alien := range alienNumber {
 		roman, ok := alienDictionary[alien]
 		if !ok {
-			return "", errors.New("invalid alien number")
+			return "", fmt.Errorf("%w '%s'", ErrUnknownAlienWord, alien)
 		}
 
 		romans += roman
@@ -96,3 +304,47 @@ func (c *converter) AlienToRoman(alienDictionary map[string]string, alienNumber
 
 	return strings.ToUpper(romans), nil
 }
+
+// RomanToAlien writes romanNumber with the words of alienDictionary, reading
+// the longest symbol first. A symbol several words stand for is written with
+// the first of them in alphabetical order.
+func (c *converter) RomanToAlien(alienDictionary map[string]string, romanNumber string) ([]string, error) {
+	words := map[string]string{}
+	for alien, roman := range alienDictionary {
+		symbol := strings.ToUpper(roman)
+		if word, ok := words[symbol]; !ok || alien < word {
+			words[symbol] = alien
+		}
+	}
+
+	symbols := make([]string, 0, len(words))
+	for symbol := range words {
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		if len(symbols[i]) != len(symbols[j]) {
+			return len(symbols[i]) > len(symbols[j])
+		}
+		return symbols[i] < symbols[j]
+	})
+
+	alienNumber := []string{}
+	for rest := romanNumber; rest != ""; {
+		found := false
+		for _, symbol := range symbols {
+			if strings.HasPrefix(rest, symbol) {
+				alienNumber = append(alienNumber, words[symbol])
+				rest = rest[len(symbol):]
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			symbol, _ := utf8.DecodeRuneInString(rest)
+			return nil, fmt.Errorf("%w for '%c'", ErrNoAlienWord, symbol)
+		}
+	}
+
+	return alienNumber, nil
+}
@@ -10,7 +10,7 @@ import (
 
 func TestRomanToArabic(t *testing.T) {
 
-	converter := converters.NewConverter()
+	converter := converters.NewConverter(converters.NewConverterParams{})
 
 	type args struct {
 		param string
@@ -35,7 +35,7 @@ func TestRomanToArabic(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {},
 			want: want{
 				result: 0,
-				error:  errors.New("invalid roman number"),
+				error:  errors.New("invalid roman number 'IIII'"),
 			},
 		},
 		{
@@ -46,7 +46,18 @@ func TestRomanToArabic(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {},
 			want: want{
 				result: 0,
-				error:  errors.New("invalid roman number"),
+				error:  errors.New("invalid roman number 'IIIXM'"),
+			},
+		},
+		{
+			name: "when roman numeral is empty should return error",
+			args: args{
+				param: "",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: 0,
+				error:  errors.New("invalid roman number ''"),
 			},
 		},
 		{
@@ -84,7 +95,7 @@ func TestRomanToArabic(t *testing.T) {
 }
 
 func TestArabicToRoman(t *testing.T) {
-	converter := converters.NewConverter()
+	converter := converters.NewConverter(converters.NewConverterParams{})
 
 	type args struct {
 		param int
@@ -109,7 +120,7 @@ func TestArabicToRoman(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {},
 			want: want{
 				result: "",
-				error:  errors.New("number out of range"),
+				error:  errors.New("number out of range '4000'"),
 			},
 		},
 		{
@@ -120,7 +131,18 @@ func TestArabicToRoman(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {},
 			want: want{
 				result: "",
-				error:  errors.New("number out of range"),
+				error:  errors.New("number out of range '0'"),
+			},
+		},
+		{
+			name: "when number is 3999 should return success",
+			args: args{
+				param: 3999,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "MMMCMXCIX",
+				error:  nil,
 			},
 		},
 		{
@@ -157,8 +179,229 @@ func TestArabicToRoman(t *testing.T) {
 	}
 }
 
+func TestExtendedNotation(t *testing.T) {
+
+	type args struct {
+		notation converters.Notation
+		arabic   int
+		roman    string
+	}
+
+	type want struct {
+		roman  string
+		arabic int
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when vinculum number is 4000 should overline the thousands",
+			args: args{
+				notation: converters.NotationVinculum,
+				arabic:   4000,
+				roman:    "I̅V̅",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				roman:  "I̅V̅",
+				arabic: 4000,
+			},
+		},
+		{
+			name: "when vinculum number is the largest should return success",
+			args: args{
+				notation: converters.NotationVinculum,
+				arabic:   3999999,
+				roman:    "M̅M̅M̅C̅M̅X̅C̅I̅X̅CMXCIX",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				roman:  "M̅M̅M̅C̅M̅X̅C̅I̅X̅CMXCIX",
+				arabic: 3999999,
+			},
+		},
+		{
+			name: "when vinculum number is below 4000 should use the classic notation",
+			args: args{
+				notation: converters.NotationVinculum,
+				arabic:   3021,
+				roman:    "MMMXXI",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				roman:  "MMMXXI",
+				arabic: 3021,
+			},
+		},
+		{
+			name: "when apostrophus number is 4021 should write the thousands with apostrophi",
+			args: args{
+				notation: converters.NotationApostrophus,
+				arabic:   4021,
+				roman:    "CIↃIↃↃXXI",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				roman:  "CIↃIↃↃXXI",
+				arabic: 4021,
+			},
+		},
+		{
+			name: "when apostrophus number is the largest should return success",
+			args: args{
+				notation: converters.NotationApostrophus,
+				arabic:   399999,
+				roman:    "CCCIↃↃↃCCCIↃↃↃCCCIↃↃↃCCIↃↃCCCIↃↃↃCIↃCCIↃↃCMXCIX",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				roman:  "CCCIↃↃↃCCCIↃↃↃCCCIↃↃↃCCIↃↃCCCIↃↃↃCIↃCCIↃↃCMXCIX",
+				arabic: 399999,
+			},
+		},
+		{
+			name: "when vinculum thousands are below 4 should return error",
+			args: args{
+				notation: converters.NotationVinculum,
+				roman:    "I̅I̅",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid roman number 'I̅I̅'"),
+			},
+		},
+		{
+			name: "when vinculum number has thousands after the overline should return error",
+			args: args{
+				notation: converters.NotationVinculum,
+				roman:    "V̅M",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid roman number 'V̅M'"),
+			},
+		},
+		{
+			name: "when apostrophus is incomplete should return error",
+			args: args{
+				notation: converters.NotationApostrophus,
+				roman:    "CCIↃ",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid roman number 'CCIↃ'"),
+			},
+		},
+		{
+			name: "when classic number uses the vinculum should return error",
+			args: args{
+				notation: converters.NotationClassic,
+				roman:    "I̅V̅",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid roman number 'I̅V̅'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			converter := converters.NewConverter(converters.NewConverterParams{Notation: tc.args.notation})
+
+			arabic, err := converter.RomanToArabic(tc.args.roman)
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+				return
+			}
+
+			if diff := deep.Equal(arabic, tc.want.arabic); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.arabic, arabic, diff)
+			}
+
+			roman, err := converter.ArabicToRoman(tc.args.arabic)
+			if err != nil {
+				t.Errorf("got unexpected error.\n actual: %v\n", err)
+			}
+
+			if diff := deep.Equal(roman, tc.want.roman); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.roman, roman, diff)
+			}
+		})
+
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+
+	classic := converters.NewConverter(converters.NewConverterParams{})
+
+	testcases := []struct {
+		notation converters.Notation
+		max      int
+	}{
+		{notation: converters.NotationClassic, max: 3999},
+		{notation: converters.NotationVinculum, max: 3999999},
+		{notation: converters.NotationApostrophus, max: 399999},
+	}
+
+	for _, tc := range testcases {
+		t.Run(string(tc.notation), func(t *testing.T) {
+
+			converter := converters.NewConverter(converters.NewConverterParams{Notation: tc.notation})
+
+			for number := 1; number <= tc.max; number += roundTripStep(number) {
+				roman, err := converter.ArabicToRoman(number)
+				if err != nil {
+					t.Fatalf("got unexpected error for %d: %v", number, err)
+				}
+
+				result, err := converter.RomanToArabic(roman)
+				if err != nil || result != number {
+					t.Fatalf("got unexpected round trip.\n expected: %d\n actual: %d (%s)\n error: %v\n", number, result, roman, err)
+				}
+
+				if number > 3999 {
+					continue
+				}
+
+				classicRoman, _ := classic.ArabicToRoman(number)
+				result, err = converter.RomanToArabic(classicRoman)
+				if err != nil || result != number {
+					t.Fatalf("got unexpected classic number.\n expected: %d\n actual: %d (%s)\n error: %v\n", number, result, classicRoman, err)
+				}
+			}
+
+			_, err := converter.ArabicToRoman(tc.max + 1)
+			if !errors.Is(err, converters.ErrOutOfRange) {
+				t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", converters.ErrOutOfRange, err)
+			}
+		})
+	}
+}
+
+// roundTripStep visits every classic number, then a sample of the larger ones.
+func roundTripStep(number int) int {
+	if number < 4000 {
+		return 1
+	}
+
+	return 997
+}
+
 func TestAlienToRoman(t *testing.T) {
-	converter := converters.NewConverter()
+	converter := converters.NewConverter(converters.NewConverterParams{})
 
 	alienDict := map[string]string{
 		"glob": "I",
@@ -201,7 +444,7 @@ func TestAlienToRoman(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {},
 			want: want{
 				result: "",
-				error:  errors.New("invalid alien number"),
+				error:  errors.New("unknown alien word 'prok'"),
 			},
 		},
 	}
@@ -226,3 +469,88 @@ func TestAlienToRoman(t *testing.T) {
 
 	}
 }
+
+func TestRomanToAlien(t *testing.T) {
+	converter := converters.NewConverter(converters.NewConverterParams{})
+
+	alienDict := map[string]string{
+		"glob": "i",
+		"prok": "v",
+		"pish": "x",
+		"tegj": "l",
+		"flar": "v̅",
+		"blob": "i",
+	}
+
+	type args struct {
+		dict        map[string]string
+		romanNumber string
+	}
+
+	type want struct {
+		result []string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when every symbol has a word should return the words",
+			args: args{
+				romanNumber: "XLII",
+				dict:        alienDict,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []string{"pish", "tegj", "blob", "blob"},
+			},
+		},
+		{
+			name: "when symbol is a vinculum should prefer it to the plain symbol",
+			args: args{
+				romanNumber: "V̅I",
+				dict:        alienDict,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []string{"flar", "blob"},
+			},
+		},
+		{
+			name: "when symbol has no word should return error",
+			args: args{
+				romanNumber: "XC",
+				dict:        alienDict,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: nil,
+				error:  errors.New("no alien word for 'C'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, err := converter.RomanToAlien(tc.args.dict, tc.args.romanNumber)
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
@@ -0,0 +1,72 @@
+package converters
+
+import (
+	"fmt"
+)
+
+type mayan struct{}
+
+var _ NumeralSystem = (*mayan)(nil)
+
+const (
+	// mayanZero is the first of the twenty mayan digits, from 𝋠 to 𝋳.
+	mayanZero   = '\U0001D2E0'
+	mayanBase   = 20
+	mayanPlaces = 5
+	maxMayan    = 3199999
+)
+
+// NewMayan returns the mayan vigesimal numeral system, every symbol is a digit
+// from 𝋠 (0) to 𝋳 (19) and the most significant digit comes first, so 𝋡𝋢 is 22.
+func NewMayan() *mayan {
+	return &mayan{}
+}
+
+func (m *mayan) Name() string {
+	return "mayan"
+}
+
+func (m *mayan) Parse(number string) (int, error) {
+	digits := []rune(number)
+	if len(digits) == 0 || len(digits) > mayanPlaces || digits[0] == mayanZero {
+		return 0, fmt.Errorf("%w '%s'", ErrInvalidNumber, number)
+	}
+
+	result := 0
+	for _, digit := range digits {
+		if !isMayanDigit(digit) {
+			return 0, fmt.Errorf("%w '%s'", ErrInvalidNumber, number)
+		}
+
+		result = result*mayanBase + int(digit-mayanZero)
+	}
+
+	return result, nil
+}
+
+func (m *mayan) Format(value int) (string, error) {
+	if value < 1 || value > maxMayan {
+		return "", fmt.Errorf("%w '%d'", ErrOutOfRange, value)
+	}
+
+	digits := []rune{}
+	for ; value > 0; value /= mayanBase {
+		digits = append([]rune{mayanZero + rune(value%mayanBase)}, digits...)
+	}
+
+	return string(digits), nil
+}
+
+func (m *mayan) IsSymbol(symbol string) bool {
+	digits := []rune(symbol)
+
+	return len(digits) == 1 && isMayanDigit(digits[0])
+}
+
+func (m *mayan) Range() (int, int) {
+	return 1, maxMayan
+}
+
+func isMayanDigit(digit rune) bool {
+	return digit >= mayanZero && digit < mayanZero+mayanBase
+}
@@ -63,6 +63,36 @@ func (mr *MockConverterServiceMockRecorder) ArabicToRoman(number interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArabicToRoman", reflect.TypeOf((*MockConverterService)(nil).ArabicToRoman), number)
 }
 
+// Range mocks base method.
+func (m *MockConverterService) Range() (int, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Range")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	return ret0, ret1
+}
+
+// Range indicates an expected call of Range.
+func (mr *MockConverterServiceMockRecorder) Range() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Range", reflect.TypeOf((*MockConverterService)(nil).Range))
+}
+
+// RomanToAlien mocks base method.
+func (m *MockConverterService) RomanToAlien(alienDictionary map[string]string, romanNumber string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RomanToAlien", alienDictionary, romanNumber)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RomanToAlien indicates an expected call of RomanToAlien.
+func (mr *MockConverterServiceMockRecorder) RomanToAlien(alienDictionary, romanNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RomanToAlien", reflect.TypeOf((*MockConverterService)(nil).RomanToAlien), alienDictionary, romanNumber)
+}
+
 // RomanToArabic mocks base method.
 func (m *MockConverterService) RomanToArabic(romanNumber string) (int, error) {
 	m.ctrl.T.Helper()
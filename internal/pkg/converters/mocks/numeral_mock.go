@@ -0,0 +1,172 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/converters/numeral.go
+
+// Package mock_converters is a generated GoMock package.
+package mock_converters
+
+import (
+	reflect "reflect"
+
+	converters "github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockNumeralSystem is a mock of NumeralSystem interface.
+type MockNumeralSystem struct {
+	ctrl     *gomock.Controller
+	recorder *MockNumeralSystemMockRecorder
+}
+
+// MockNumeralSystemMockRecorder is the mock recorder for MockNumeralSystem.
+type MockNumeralSystemMockRecorder struct {
+	mock *MockNumeralSystem
+}
+
+// NewMockNumeralSystem creates a new mock instance.
+func NewMockNumeralSystem(ctrl *gomock.Controller) *MockNumeralSystem {
+	mock := &MockNumeralSystem{ctrl: ctrl}
+	mock.recorder = &MockNumeralSystemMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNumeralSystem) EXPECT() *MockNumeralSystemMockRecorder {
+	return m.recorder
+}
+
+// Format mocks base method.
+func (m *MockNumeralSystem) Format(value int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Format", value)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Format indicates an expected call of Format.
+func (mr *MockNumeralSystemMockRecorder) Format(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Format", reflect.TypeOf((*MockNumeralSystem)(nil).Format), value)
+}
+
+// IsSymbol mocks base method.
+func (m *MockNumeralSystem) IsSymbol(symbol string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSymbol", symbol)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSymbol indicates an expected call of IsSymbol.
+func (mr *MockNumeralSystemMockRecorder) IsSymbol(symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSymbol", reflect.TypeOf((*MockNumeralSystem)(nil).IsSymbol), symbol)
+}
+
+// Name mocks base method.
+func (m *MockNumeralSystem) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockNumeralSystemMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockNumeralSystem)(nil).Name))
+}
+
+// Parse mocks base method.
+func (m *MockNumeralSystem) Parse(number string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Parse", number)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Parse indicates an expected call of Parse.
+func (mr *MockNumeralSystemMockRecorder) Parse(number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MockNumeralSystem)(nil).Parse), number)
+}
+
+// Range mocks base method.
+func (m *MockNumeralSystem) Range() (int, int) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Range")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	return ret0, ret1
+}
+
+// Range indicates an expected call of Range.
+func (mr *MockNumeralSystemMockRecorder) Range() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Range", reflect.TypeOf((*MockNumeralSystem)(nil).Range))
+}
+
+// MockNumeralRegistryService is a mock of NumeralRegistryService interface.
+type MockNumeralRegistryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockNumeralRegistryServiceMockRecorder
+}
+
+// MockNumeralRegistryServiceMockRecorder is the mock recorder for MockNumeralRegistryService.
+type MockNumeralRegistryServiceMockRecorder struct {
+	mock *MockNumeralRegistryService
+}
+
+// NewMockNumeralRegistryService creates a new mock instance.
+func NewMockNumeralRegistryService(ctrl *gomock.Controller) *MockNumeralRegistryService {
+	mock := &MockNumeralRegistryService{ctrl: ctrl}
+	mock.recorder = &MockNumeralRegistryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNumeralRegistryService) EXPECT() *MockNumeralRegistryServiceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockNumeralRegistryService) Get(name string) (converters.NumeralSystem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", name)
+	ret0, _ := ret[0].(converters.NumeralSystem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockNumeralRegistryServiceMockRecorder) Get(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNumeralRegistryService)(nil).Get), name)
+}
+
+// Names mocks base method.
+func (m *MockNumeralRegistryService) Names() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Names")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Names indicates an expected call of Names.
+func (mr *MockNumeralRegistryServiceMockRecorder) Names() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Names", reflect.TypeOf((*MockNumeralRegistryService)(nil).Names))
+}
+
+// Register mocks base method.
+func (m *MockNumeralRegistryService) Register(system converters.NumeralSystem) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Register", system)
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockNumeralRegistryServiceMockRecorder) Register(system interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockNumeralRegistryService)(nil).Register), system)
+}
@@ -0,0 +1,132 @@
+package converters
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NumeralSystem reads and writes numbers in the symbols of a civilization,
+// alien words stand for its symbols.
+type NumeralSystem interface {
+	Name() string
+	// Parse returns the value of number, the symbols written by a sequence of alien words.
+	Parse(number string) (int, error)
+	// Format writes value in the symbols of the system.
+	Format(value int) (string, error)
+	// IsSymbol reports whether an alien word can stand for symbol.
+	IsSymbol(symbol string) bool
+	// Range returns the smallest and the largest number the system can write.
+	Range() (int, int)
+}
+
+// NumeralRegistryService finds the numeral systems by name.
+type NumeralRegistryService interface {
+	Register(system NumeralSystem)
+	Get(name string) (NumeralSystem, error)
+	Names() []string
+}
+
+type numeralRegistry struct {
+	systems map[string]NumeralSystem
+}
+
+// RomanSystem is the name of the default numeral system.
+const RomanSystem = "roman"
+
+var ErrUnknownNumeralSystem = errors.New("unknown numeral system")
+
+var _ NumeralRegistryService = (*numeralRegistry)(nil)
+
+type NewNumeralRegistryParams struct {
+	// Converter reads and writes the roman numbers.
+	Converter ConverterService
+}
+
+// NewNumeralRegistry returns a registry of the roman, attic, babylonian and mayan numeral systems.
+func NewNumeralRegistry(p NewNumeralRegistryParams) *numeralRegistry {
+	registry := &numeralRegistry{
+		systems: map[string]NumeralSystem{},
+	}
+
+	registry.Register(NewRoman(p.Converter))
+	registry.Register(NewAttic())
+	registry.Register(NewBabylonian())
+	registry.Register(NewMayan())
+
+	return registry
+}
+
+// Register adds system to the registry, replacing any system of the same name.
+func (r *numeralRegistry) Register(system NumeralSystem) {
+	r.systems[strings.ToLower(system.Name())] = system
+}
+
+// Get returns the system named name, ignoring case.
+func (r *numeralRegistry) Get(name string) (NumeralSystem, error) {
+	system, ok := r.systems[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownNumeralSystem, name)
+	}
+
+	return system, nil
+}
+
+// Names returns the sorted names of the registered systems.
+func (r *numeralRegistry) Names() []string {
+	names := make([]string, 0, len(r.systems))
+	for name := range r.systems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+type roman struct {
+	converter ConverterService
+}
+
+var _ NumeralSystem = (*roman)(nil)
+
+// romanSymbols lists the symbols an alien word can stand for, including the
+// vinculum and apostrophus ones read by the extended notations.
+var romanSymbols = []string{
+	"I", "V", "X", "L", "C", "D", "M",
+	"I̅", "V̅", "X̅", "L̅", "C̅", "D̅", "M̅",
+	"Ↄ", "CIↃ", "IↃↃ", "CCIↃↃ", "IↃↃↃ", "CCCIↃↃↃ",
+}
+
+// NewRoman returns the roman numeral system written by converter.
+func NewRoman(converter ConverterService) *roman {
+	return &roman{
+		converter: converter,
+	}
+}
+
+func (r *roman) Name() string {
+	return RomanSystem
+}
+
+func (r *roman) Parse(number string) (int, error) {
+	return r.converter.RomanToArabic(number)
+}
+
+func (r *roman) Format(value int) (string, error) {
+	return r.converter.ArabicToRoman(value)
+}
+
+func (r *roman) IsSymbol(symbol string) bool {
+	for _, romanSymbol := range romanSymbols {
+		if symbol == romanSymbol {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *roman) Range() (int, int) {
+	return r.converter.Range()
+}
@@ -0,0 +1,199 @@
+package converters_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/go-test/deep"
+)
+
+func TestNumeralSystems(t *testing.T) {
+
+	registry := converters.NewNumeralRegistry(converters.NewNumeralRegistryParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+	})
+
+	type args struct {
+		system string
+		number string
+		value  int
+	}
+
+	type want struct {
+		value  int
+		number string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when roman number is valid should return success",
+			args: args{
+				system: "roman",
+				number: "MCMXCIV",
+				value:  1994,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				value:  1994,
+				number: "MCMXCIV",
+			},
+		},
+		{
+			name: "when attic number is valid should return success",
+			args: args{
+				system: "attic",
+				number: "ΧΧ𐅅ΗΗ𐅄ΔΠΙΙ",
+				value:  2767,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				value:  2767,
+				number: "ΧΧ𐅅ΗΗ𐅄ΔΠΙΙ",
+			},
+		},
+		{
+			name: "when attic symbol is repeated five times should return error",
+			args: args{
+				system: "attic",
+				number: "ΙΙΙΙΙ",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid number 'ΙΙΙΙΙ'"),
+			},
+		},
+		{
+			name: "when babylonian number has an empty place should read it as zero",
+			args: args{
+				system: "babylonian",
+				number: "𒁹..𒌋𒌋𒁹𒁹",
+				value:  3622,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				value:  3622,
+				number: "𒁹..𒌋𒌋𒁹𒁹",
+			},
+		},
+		{
+			name: "when babylonian digit is 60 should return error",
+			args: args{
+				system: "babylonian",
+				number: "𒌋𒌋𒌋𒌋𒌋𒌋",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid number '𒌋𒌋𒌋𒌋𒌋𒌋'"),
+			},
+		},
+		{
+			name: "when mayan number is valid should return success",
+			args: args{
+				system: "mayan",
+				number: "𝋡𝋠𝋳",
+				value:  419,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				value:  419,
+				number: "𝋡𝋠𝋳",
+			},
+		},
+		{
+			name: "when mayan number starts with zero should return error",
+			args: args{
+				system: "mayan",
+				number: "𝋠𝋡",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid number '𝋠𝋡'"),
+			},
+		},
+		{
+			name: "when system is unknown should return error",
+			args: args{
+				system: "egyptian",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("unknown numeral system 'egyptian'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			system, err := registry.Get(tc.args.system)
+
+			var value int
+			if err == nil {
+				value, err = system.Parse(tc.args.number)
+			}
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+				return
+			}
+
+			if diff := deep.Equal(value, tc.want.value); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.value, value, diff)
+			}
+
+			number, err := system.Format(tc.args.value)
+			if err != nil {
+				t.Errorf("got unexpected error.\n actual: %v\n", err)
+			}
+
+			if diff := deep.Equal(number, tc.want.number); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.number, number, diff)
+			}
+		})
+
+	}
+}
+
+func TestNumeralSystemsRoundTrip(t *testing.T) {
+
+	registry := converters.NewNumeralRegistry(converters.NewNumeralRegistryParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+	})
+
+	for _, name := range registry.Names() {
+		t.Run(name, func(t *testing.T) {
+
+			system, _ := registry.Get(name)
+			lowest, highest := system.Range()
+
+			// the step grows with the value to sample the whole range
+			for value := lowest; value <= highest; value += 1 + value/100 {
+				number, err := system.Format(value)
+				if err != nil {
+					t.Fatalf("got unexpected error for %d: %v", value, err)
+				}
+
+				result, err := system.Parse(number)
+				if err != nil || result != value {
+					t.Fatalf("got unexpected round trip.\n expected: %d\n actual: %d (%s)\n error: %v\n", value, result, number, err)
+				}
+			}
+
+			_, err := system.Format(highest + 1)
+			if !errors.Is(err, converters.ErrOutOfRange) {
+				t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", converters.ErrOutOfRange, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package parsers
+
+import (
+	"errors"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+)
+
+type QuestionKind string
+
+const (
+	QuestionKindUnknown QuestionKind = "unknown"
+	// QuestionKindHowMuch asks for the value of an alien number.
+	QuestionKindHowMuch QuestionKind = "how_much"
+	// QuestionKindArithmetic asks for the value of an expression over alien numbers.
+	QuestionKindArithmetic QuestionKind = "arithmetic"
+	// QuestionKindHowMany asks for the credits of an amount of commodity.
+	QuestionKindHowMany QuestionKind = "how_many"
+	// QuestionKindDoes compares the credits of two amounts of commodity.
+	QuestionKindDoes QuestionKind = "does"
+	// QuestionKindIs compares the values of two alien numbers.
+	QuestionKindIs QuestionKind = "is"
+	// QuestionKindSay asks for the alien words of a number.
+	QuestionKindSay QuestionKind = "say"
+	// QuestionKindHowManyFor asks for the amount of commodity an amount of credits buys.
+	QuestionKindHowManyFor QuestionKind = "how_many_for"
+	// QuestionKindExchange asks for the amount of commodity an amount of another commodity is worth.
+	QuestionKindExchange QuestionKind = "exchange"
+)
+
+type Comparison string
+
+const (
+	ComparisonNone  Comparison = ""
+	ComparisonLess  Comparison = "less"
+	ComparisonMore  Comparison = "more"
+	ComparisonEqual Comparison = "equal"
+)
+
+type ErrorCategory string
+
+const (
+	ErrorCategoryNone              ErrorCategory = ""
+	ErrorCategoryUnknownAlienWord  ErrorCategory = "unknown_alien_word"
+	ErrorCategoryInvalidRoman      ErrorCategory = "invalid_roman"
+	ErrorCategoryOutOfRange        ErrorCategory = "out_of_range"
+	ErrorCategoryUnknownCommodity  ErrorCategory = "unknown_commodity"
+	ErrorCategoryNoAlienWord       ErrorCategory = "no_alien_word"
+	ErrorCategoryUnknownCurrency   ErrorCategory = "unknown_currency"
+	ErrorCategoryNoExchangeRate    ErrorCategory = "no_exchange_rate"
+	ErrorCategoryUnknownDialect    ErrorCategory = "unknown_dialect"
+	ErrorCategoryMixedDialects     ErrorCategory = "mixed_dialects"
+	ErrorCategoryNoPrice           ErrorCategory = "no_price"
+	ErrorCategoryMalformedQuestion ErrorCategory = "malformed_question"
+	ErrorCategoryUnknown           ErrorCategory = "unknown"
+)
+
+// Line is a line of the input along with its position.
+type Line struct {
+	File   string
+	Number int
+	Text   string
+	// Raw is the line as written in the input, before it was normalized into Text.
+	Raw string
+}
+
+// Operand is an alien number referenced by a question, optionally followed by a commodity.
+type Operand struct {
+	Alien []string
+	Value int
+	// Dialect is the dialect of Alien, empty for the default one.
+	Dialect   string
+	Commodity string
+	// Credits is the price of Value units of Commodity.
+	Credits rationals.Rational
+}
+
+// Answer is the structured result of a question, Err is set when the question cannot be answered.
+type Answer struct {
+	File      string
+	Line      int
+	Question  string
+	Kind      QuestionKind
+	Operands  []Operand
+	Value     rationals.Rational
+	Commodity string
+	// Currency is the currency of Value when it is not credits.
+	Currency string
+	// AsOf is the date the commodities are priced at, empty for the current prices.
+	AsOf       string
+	Comparison Comparison
+	// Expression is the arithmetic expression asked, as written in the question.
+	Expression string
+	// Alien is the answer written in alien words.
+	Alien    []string
+	Err      error
+	Category ErrorCategory
+}
+
+// Categorize maps err to the category reported in an answer.
+func Categorize(err error) ErrorCategory {
+	switch {
+	case err == nil:
+		return ErrorCategoryNone
+	case errors.Is(err, converters.ErrUnknownAlienWord):
+		return ErrorCategoryUnknownAlienWord
+	case errors.Is(err, converters.ErrInvalidRoman), errors.Is(err, converters.ErrInvalidNumber):
+		return ErrorCategoryInvalidRoman
+	case errors.Is(err, converters.ErrOutOfRange):
+		return ErrorCategoryOutOfRange
+	case errors.Is(err, converters.ErrNoAlienWord):
+		return ErrorCategoryNoAlienWord
+	case errors.Is(err, ErrUnknownCommodity):
+		return ErrorCategoryUnknownCommodity
+	case errors.Is(err, ErrUnknownCurrency):
+		return ErrorCategoryUnknownCurrency
+	case errors.Is(err, ErrNoExchangeRate):
+		return ErrorCategoryNoExchangeRate
+	case errors.Is(err, ErrUnknownDialect):
+		return ErrorCategoryUnknownDialect
+	case errors.Is(err, ErrMixedDialects):
+		return ErrorCategoryMixedDialects
+	case errors.Is(err, ErrNoPrice):
+		return ErrorCategoryNoPrice
+	case errors.Is(err, ErrMalformedQuestion):
+		return ErrorCategoryMalformedQuestion
+	default:
+		return ErrorCategoryUnknown
+	}
+}
+
+func compare(value1 rationals.Rational, value2 rationals.Rational) Comparison {
+	switch value1.Cmp(value2) {
+	case -1:
+		return ComparisonLess
+	case 1:
+		return ComparisonMore
+	default:
+		return ComparisonEqual
+	}
+}
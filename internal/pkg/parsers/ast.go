@@ -0,0 +1,179 @@
+package parsers
+
+import (
+	"strings"
+)
+
+// Statement is a parsed line, either a definition or a question.
+type Statement interface {
+	statement()
+}
+
+// AlienNumber is a sequence of alien words of a dialect, the default dialect
+// when Dialect is the zero token.
+type AlienNumber struct {
+	Dialect Token
+	Words   []Token
+}
+
+// Expression is an arithmetic expression over alien numbers, either an
+// AlienNumber, a *BinaryExpression or a *GroupExpression.
+type Expression interface {
+	String() string
+	expression()
+}
+
+// BinaryExpression is "<expression> plus|minus|times <expression>".
+type BinaryExpression struct {
+	Left     Expression
+	Operator Token
+	Right    Expression
+}
+
+// GroupExpression is "( <expression> )".
+type GroupExpression struct {
+	Open       Token
+	Expression Expression
+}
+
+// Quantity is an alien number of a commodity.
+type Quantity struct {
+	Number    AlienNumber
+	Commodity Token
+}
+
+// CurrencyDefinition is "<alien word> is <roman symbol>".
+type CurrencyDefinition struct {
+	Dialect Token
+	Word    Token
+	Roman   Token
+}
+
+// CommodityDefinition is "<alien number> <commodity> is <credits> <currency>",
+// the price is effective from Date when it is set.
+type CommodityDefinition struct {
+	Date     Token
+	Quantity Quantity
+	Credits  Token
+	Currency Token
+}
+
+// Money is an amount of a currency.
+type Money struct {
+	Amount   Token
+	Currency Token
+}
+
+// RateDefinition is "<amount> <currency> is <amount> <currency>".
+type RateDefinition struct {
+	Left  Money
+	Right Money
+}
+
+// HowMuchQuestion is "how much is <alien number> ?".
+type HowMuchQuestion struct {
+	Number AlienNumber
+}
+
+// ArithmeticQuestion is "how much is <expression> ?" where the expression is
+// more than a single alien number.
+type ArithmeticQuestion struct {
+	Expression Expression
+}
+
+// HowManyQuestion is "how many credits|<currency> is <alien number> <commodity> ?",
+// priced as of AsOf when it is set.
+type HowManyQuestion struct {
+	Currency Token
+	Quantity Quantity
+	AsOf     Token
+}
+
+// DoesQuestion is "does <quantity> has more|less credits than <quantity> ?",
+// priced as of AsOf when it is set.
+type DoesQuestion struct {
+	Left     Quantity
+	Relation Token
+	Right    Quantity
+	AsOf     Token
+}
+
+// IsQuestion is "is <alien number> larger|smaller than <alien number> ?".
+type IsQuestion struct {
+	Left     AlienNumber
+	Relation Token
+	Right    AlienNumber
+}
+
+// SayQuestion is "how do you say <number> ?", answered in the words of Dialect.
+type SayQuestion struct {
+	Dialect Token
+	Number  Token
+}
+
+// HowManyForQuestion is "how many <commodity> for <credits> credits ?",
+// answered in the words of Dialect and priced as of AsOf when it is set.
+type HowManyForQuestion struct {
+	Dialect   Token
+	Commodity Token
+	Credits   Token
+	AsOf      Token
+}
+
+// ExchangeQuestion is "how many <commodity> is|for <quantity> ?", priced as of
+// AsOf when it is set.
+type ExchangeQuestion struct {
+	Commodity Token
+	Quantity  Quantity
+	AsOf      Token
+}
+
+// NumeralsDeclaration is "numerals are <system>", Dialect is set when the
+// declaration is scoped by "on <dialect>".
+type NumeralsDeclaration struct {
+	Dialect Token
+	System  Token
+}
+
+func (*NumeralsDeclaration) statement() {}
+func (*CurrencyDefinition) statement()  {}
+func (*CommodityDefinition) statement() {}
+func (*RateDefinition) statement()      {}
+func (*HowMuchQuestion) statement()     {}
+func (*ArithmeticQuestion) statement()  {}
+func (*HowManyQuestion) statement()     {}
+func (*DoesQuestion) statement()        {}
+func (*IsQuestion) statement()          {}
+func (*SayQuestion) statement()         {}
+func (*HowManyForQuestion) statement()  {}
+func (*ExchangeQuestion) statement()    {}
+
+func (AlienNumber) expression()       {}
+func (*BinaryExpression) expression() {}
+func (*GroupExpression) expression()  {}
+
+// Strings returns the text of every word of the number.
+func (n AlienNumber) Strings() []string {
+	words := make([]string, 0, len(n.Words))
+	for _, word := range n.Words {
+		words = append(words, word.Text)
+	}
+
+	return words
+}
+
+func (n AlienNumber) String() string {
+	if n.Dialect.Text != "" {
+		return n.Dialect.Text + ": " + strings.Join(n.Strings(), " ")
+	}
+
+	return strings.Join(n.Strings(), " ")
+}
+
+func (e *BinaryExpression) String() string {
+	return e.Left.String() + " " + e.Operator.Text + " " + e.Right.String()
+}
+
+func (e *GroupExpression) String() string {
+	return "(" + e.Expression.String() + ")"
+}
@@ -0,0 +1,141 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ConflictPolicy decides what happens to a definition that conflicts with an earlier one.
+type ConflictPolicy string
+
+const (
+	// ConflictError rejects the definition with an error.
+	ConflictError ConflictPolicy = "error"
+	// ConflictWarn learns the definition and reports the conflict as a warning.
+	ConflictWarn ConflictPolicy = "warn"
+	// ConflictLastWins silently learns the definition.
+	ConflictLastWins ConflictPolicy = "last_wins"
+	// ConflictFirstWins silently ignores the definition.
+	ConflictFirstWins ConflictPolicy = "first_wins"
+)
+
+type ConflictKind string
+
+const (
+	// ConflictRedefinedWord is an alien word given another symbol.
+	ConflictRedefinedWord ConflictKind = "redefined_word"
+	// ConflictRedefinedPrice is a commodity given another price.
+	ConflictRedefinedPrice ConflictKind = "redefined_price"
+	// ConflictSharedSymbol is an alien word given the symbol of another word.
+	ConflictSharedSymbol ConflictKind = "shared_symbol"
+	// ConflictRedefinedRate is an exchange rate given another value.
+	ConflictRedefinedRate ConflictKind = "redefined_rate"
+)
+
+var (
+	ErrConflict              = errors.New("conflicting definition")
+	ErrUnknownConflictPolicy = errors.New("unknown conflict policy")
+)
+
+// Source is the line a definition was learned from, Column is the 1-based
+// position of the defined word. A zero Line is a definition restored from a
+// knowledge base.
+type Source struct {
+	File   string
+	Line   int
+	Column int
+	Text   string
+}
+
+func (s Source) String() string {
+	if s.Line == 0 {
+		return "the knowledge base"
+	}
+
+	return fmt.Sprintf("%s \"%s\"", position(s.File, s.Line, s.Column), s.Text)
+}
+
+// Conflict is a definition of Name contradicting an earlier one, Value and
+// PreviousValue are the symbols, prices or rates of both definitions, the
+// prices and rates being rounded like the answers. For a
+// shared symbol, Other is the word that already stands for Value, for a rate
+// it is the currency Name is exchanged for.
+type Conflict struct {
+	Kind          ConflictKind
+	Name          string
+	Value         string
+	PreviousValue string
+	Other         string
+	Previous      Source
+	Current       Source
+	// Policy is the policy the conflict was resolved with.
+	Policy ConflictPolicy
+}
+
+// String describes the conflict at the position of the current definition along with its resolution.
+func (c Conflict) String() string {
+	message := fmt.Sprintf("%s: %s", position(c.Current.File, c.Current.Line, c.Current.Column), c.describe())
+
+	switch c.Policy {
+	case ConflictError:
+		return message + ", the definition is rejected"
+	case ConflictFirstWins:
+		return message + ", the first definition is kept"
+	}
+
+	switch c.Kind {
+	case ConflictSharedSymbol:
+		return message + ", both words are kept"
+	default:
+		return message + ", the last definition is kept"
+	}
+}
+
+func (c Conflict) describe() string {
+	switch c.Kind {
+	case ConflictRedefinedPrice:
+		return fmt.Sprintf("price of '%s' is redefined as %s Credits by \"%s\", it was %s Credits by %s", c.Name, c.Value, c.Current.Text, c.PreviousValue, c.Previous)
+	case ConflictRedefinedRate:
+		return fmt.Sprintf("rate of '%s' to '%s' is redefined as %s by \"%s\", it was %s by %s", c.Name, c.Other, c.Value, c.Current.Text, c.PreviousValue, c.Previous)
+	case ConflictSharedSymbol:
+		return fmt.Sprintf("'%s' stands for '%s' like '%s' by \"%s\", defined by %s", c.Name, c.Value, c.Other, c.Current.Text, c.Previous)
+	default:
+		return fmt.Sprintf("'%s' is redefined as '%s' by \"%s\", it was '%s' by %s", c.Name, c.Value, c.Current.Text, c.PreviousValue, c.Previous)
+	}
+}
+
+// ParseConflictPolicy returns the policy named name.
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(name); policy {
+	case ConflictError, ConflictWarn, ConflictLastWins, ConflictFirstWins:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w '%s'", ErrUnknownConflictPolicy, name)
+	}
+}
+
+// resolve records conflict in the report and tells whether its definition is
+// learned, the error is only set by the error policy.
+func (p *parser) resolve(conflict Conflict) (bool, error) {
+	conflict.Policy = p.conflictPolicy
+	p.conflicts = append(p.conflicts, conflict)
+
+	switch p.conflictPolicy {
+	case ConflictError:
+		return false, &Diagnostic{
+			File:   conflict.Current.File,
+			Line:   conflict.Current.Line,
+			Column: conflict.Current.Column,
+			Err:    fmt.Errorf("%w, %s", ErrConflict, conflict.describe()),
+		}
+	case ConflictFirstWins:
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+// Conflicts returns every conflict met since the last reset, in order.
+func (p *parser) Conflicts() []Conflict {
+	return append([]Conflict{}, p.conflicts...)
+}
@@ -0,0 +1,200 @@
+package parsers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// defaultMinCorrectionConfidence is used when NewParserParams.MinCorrectionConfidence is not set.
+const defaultMinCorrectionConfidence = 0.7
+
+// Correction is a word replaced by FixTypo, Column is the 1-based position of the word in the original line.
+type Correction struct {
+	File   string
+	Line   int
+	Column int
+	From   string
+	To     string
+	// Confidence is 1 for a word split into known words and decreases with the edit distance otherwise.
+	Confidence float64
+}
+
+func (c Correction) String() string {
+	return fmt.Sprintf("%s: corrected '%s' to '%s' (confidence %.0f%%)", position(c.File, c.Line, c.Column), c.From, c.To, c.Confidence*100)
+}
+
+// FixTypo replaces the words of line that are neither keywords nor known words
+// by the keyword or alien word they were most likely meant to be. A word is
+// either split into two known words, such as "Istegj" into "Is tegj", or
+// replaced by the single closest candidate whose confidence reaches the
+// threshold. The words learned by a definition and the dialect names are never
+// corrected and in strict mode line is returned unchanged.
+func (p *parser) FixTypo(line Line) (Line, []Correction) {
+	if p.strictTypos {
+		return line, nil
+	}
+
+	tokens := Lex(line.Text)
+	defined := p.definedWords(tokens)
+
+	corrections := []Correction{}
+	for idx, token := range tokens {
+		if token.Kind != TokenWord || slices.Contains(defined, idx) || isDialectName(tokens, idx) {
+			continue
+		}
+
+		corrected, confidence, ok := p.correct(token.Text)
+		if !ok {
+			continue
+		}
+
+		corrections = append(corrections, Correction{
+			File:       line.File,
+			Line:       line.Number,
+			Column:     token.Column,
+			From:       token.Text,
+			To:         corrected,
+			Confidence: confidence,
+		})
+	}
+
+	if len(corrections) == 0 {
+		return line, nil
+	}
+
+	// splice from the last correction so the columns of the previous ones stay valid
+	runes := []rune(line.Text)
+	for idx := len(corrections) - 1; idx >= 0; idx-- {
+		correction := corrections[idx]
+		start := correction.Column - 1
+		end := start + len([]rune(correction.From))
+		runes = append(runes[:start], append([]rune(correction.To), runes[end:]...)...)
+	}
+	line.Text = string(runes)
+
+	return line, corrections
+}
+
+// correct returns the correction of word, ok is false when word is known or no candidate is close enough.
+func (p *parser) correct(word string) (string, float64, bool) {
+	if p.isKnownWord(word) {
+		return "", 0, false
+	}
+
+	for _, keyword := range keywords {
+		if len(word) <= len(keyword) {
+			continue
+		}
+
+		if strings.EqualFold(word[:len(keyword)], keyword) && p.isKnownWord(word[len(keyword):]) {
+			return word[:len(keyword)] + " " + word[len(keyword):], 1, true
+		}
+
+		rest := len(word) - len(keyword)
+		if strings.EqualFold(word[rest:], keyword) && p.isKnownWord(word[:rest]) {
+			return word[:rest] + " " + word[rest:], 1, true
+		}
+	}
+
+	candidates := append(p.dialectWords(), keywords...)
+	slices.Sort(candidates)
+
+	best := ""
+	bestConfidence := 0.0
+	ambiguous := false
+	for _, candidate := range candidates {
+		confidence := similarity(strings.ToLower(word), strings.ToLower(candidate))
+		switch {
+		case confidence > bestConfidence:
+			best = candidate
+			bestConfidence = confidence
+			ambiguous = false
+		case confidence == bestConfidence && candidate != best:
+			ambiguous = true
+		}
+	}
+
+	if best == "" || ambiguous || bestConfidence < p.minCorrectionConfidence {
+		return "", 0, false
+	}
+
+	return best, bestConfidence, true
+}
+
+// isKnownWord reports whether word is a keyword, a numeral symbol, a known
+// alien word of any dialect, a known commodity or a known currency.
+func (p *parser) isKnownWord(word string) bool {
+	if slices.ContainsFunc(keywords, func(keyword string) bool { return strings.EqualFold(word, keyword) }) {
+		return true
+	}
+
+	if p.isSymbol(word) {
+		return true
+	}
+
+	if p.isAlienWord(word) || p.isCurrency(word) {
+		return true
+	}
+
+	for _, metal := range p.pricedCommodities() {
+		if strings.EqualFold(word, metal) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// similarity is 1 for equal words and decreases to 0 as the edit distance reaches the length of the longest word.
+func similarity(a string, b string) float64 {
+	longest := len([]rune(a))
+	if length := len([]rune(b)); length > longest {
+		longest = length
+	}
+
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// definedWords returns the indexes of the tokens learned by a definition such
+// as the word of "glob is I", the commodity and the currency of "glob glob Gold
+// is 10 Credits" or both currencies of "1 Credits is 3 Blips", it is empty
+// when tokens is not a definition.
+func (p *parser) definedWords(tokens []Token) []int {
+	if len(tokens) == 0 || tokens[0].Is("how") || tokens[0].Is("does") || tokens[0].Is("is") {
+		return nil
+	}
+
+	if tokens[0].Kind == TokenNumber {
+		defined := []int{}
+		for idx := 1; idx < len(tokens); idx++ {
+			if tokens[idx].Kind == TokenWord && tokens[idx-1].Kind == TokenNumber {
+				defined = append(defined, idx)
+			}
+		}
+
+		return defined
+	}
+
+	for idx := 1; idx+1 < len(tokens); idx++ {
+		if !tokens[idx].Is("is") {
+			continue
+		}
+
+		value := tokens[idx+1]
+		switch {
+		case value.Kind == TokenNumber && idx+2 < len(tokens):
+			return []int{idx - 1, idx + 2}
+		case value.Kind == TokenNumber || p.isSymbol(value.Text):
+			return []int{idx - 1}
+		}
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
+)
+
+// baseCurrency is the currency of the commodity prices.
+const baseCurrency = "credits"
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrNoExchangeRate  = errors.New("no exchange rate")
+)
+
+// learnRate learns the exchange rate declared by definition, in both
+// directions. A rate given another value is resolved by the conflict policy.
+func (p *parser) learnRate(line Line, definition *RateDefinition) (bool, error) {
+	amount, err := rationals.Parse(definition.Left.Amount.Text)
+	if err != nil {
+		return false, locate(line, positioned(definition.Left.Amount, err))
+	}
+
+	equivalent, err := rationals.Parse(definition.Right.Amount.Text)
+	if err != nil {
+		return false, locate(line, positioned(definition.Right.Amount, err))
+	}
+
+	// nothing is worth no money at all
+	if amount.Sign() == 0 {
+		return false, locate(line, positioned(definition.Left.Amount, fmt.Errorf("%w '%s'", converters.ErrOutOfRange, amount)))
+	}
+	if equivalent.Sign() == 0 {
+		return false, locate(line, positioned(definition.Right.Amount, fmt.Errorf("%w '%s'", converters.ErrOutOfRange, equivalent)))
+	}
+
+	from := strings.ToLower(definition.Left.Currency.Text)
+	to := strings.ToLower(definition.Right.Currency.Text)
+	if from == to {
+		return false, nil
+	}
+
+	rate := equivalent.Quo(amount)
+	source := Source{File: line.File, Line: line.Number, Column: definition.Left.Currency.Column, Text: line.Text}
+
+	if previous, ok := p.rates[from][to]; ok && !previous.Equal(rate) {
+		learned, err := p.resolve(Conflict{
+			Kind:          ConflictRedefinedRate,
+			Name:          from,
+			Other:         to,
+			Value:         p.rounding.Format(rate),
+			PreviousValue: p.rounding.Format(previous),
+			Previous:      p.rateSources[rateKey(from, to)],
+			Current:       source,
+		})
+		if !learned {
+			return err == nil, err
+		}
+	}
+
+	p.addRate(from, to, rate, source)
+
+	return true, nil
+}
+
+// addRate learns that a unit of from is worth rate units of to, and the other way around.
+func (p *parser) addRate(from string, to string, rate rationals.Rational, source Source) {
+	for _, currency := range []string{from, to} {
+		if p.rates[currency] == nil {
+			p.rates[currency] = map[string]rationals.Rational{}
+		}
+	}
+
+	p.rates[from][to] = rate
+	p.rates[to][from] = rationals.FromInt(1).Quo(rate)
+	p.rateSources[rateKey(from, to)] = source
+	p.rateSources[rateKey(to, from)] = source
+}
+
+// isCurrency reports whether name is the base currency or has an exchange rate.
+func (p *parser) isCurrency(name string) bool {
+	name = strings.ToLower(name)
+
+	return name == baseCurrency || len(p.rates[name]) > 0
+}
+
+// exchange converts amount of from into to, multiplying the rates along the
+// path with the fewest rates. A rate never declared is never guessed, even
+// when the declared rates contradict each other along another path.
+func (p *parser) exchange(amount rationals.Rational, from string, to string) (rationals.Rational, error) {
+	from = strings.ToLower(from)
+	to = strings.ToLower(to)
+
+	for _, currency := range []string{from, to} {
+		if !p.isCurrency(currency) {
+			return rationals.Rational{}, fmt.Errorf("%w '%s'", ErrUnknownCurrency, currency)
+		}
+	}
+
+	values := map[string]rationals.Rational{from: amount}
+	queue := []string{from}
+	for len(queue) > 0 {
+		currency := queue[0]
+		queue = queue[1:]
+
+		if currency == to {
+			return values[currency], nil
+		}
+
+		// sorted so that the same path is taken on every run
+		neighbours := keys(p.rates[currency])
+		slices.Sort(neighbours)
+
+		for _, next := range neighbours {
+			if _, ok := values[next]; ok {
+				continue
+			}
+
+			values[next] = values[currency].Mul(p.rates[currency][next])
+			queue = append(queue, next)
+		}
+	}
+
+	return rationals.Rational{}, fmt.Errorf("%w from '%s' to '%s'", ErrNoExchangeRate, from, to)
+}
+
+// knownRates returns every rate once, from the currency first in alphabetical order.
+func (p *parser) knownRates() []storages.Rate {
+	var rates []storages.Rate
+
+	currencies := keys(p.rates)
+	slices.Sort(currencies)
+
+	for _, from := range currencies {
+		targets := keys(p.rates[from])
+		slices.Sort(targets)
+
+		for _, to := range targets {
+			if from < to {
+				rates = append(rates, storages.Rate{From: from, To: to, Rate: p.rates[from][to]})
+			}
+		}
+	}
+
+	return rates
+}
+
+func rateKey(from string, to string) string {
+	return from + " " + to
+}
@@ -0,0 +1,65 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Diagnostic is an error located in the input, Column is 1-based.
+type Diagnostic struct {
+	File       string
+	Line       int
+	Column     int
+	Err        error
+	Suggestion string
+}
+
+func (d *Diagnostic) Error() string {
+	message := fmt.Sprintf("%s: %s", position(d.File, d.Line, d.Column), d.Err)
+	if d.Suggestion != "" {
+		message += fmt.Sprintf(", did you mean '%s'?", d.Suggestion)
+	}
+
+	return message
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// position formats a location as "file:line:column", the file is omitted when empty.
+func position(file string, line int, column int) string {
+	if file == "" {
+		return fmt.Sprintf("%d:%d", line, column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", file, line, column)
+}
+
+// positioned attaches the column of token to err.
+func positioned(token Token, err error) *Diagnostic {
+	return &Diagnostic{Column: token.Column, Err: err}
+}
+
+// locate attaches the file and line number of line to err, errors without a
+// column are reported at the start of the line.
+func locate(line Line, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var diagnostic *Diagnostic
+	if !errors.As(err, &diagnostic) {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			diagnostic = positioned(syntaxErr.Found, err)
+		} else {
+			diagnostic = &Diagnostic{Column: 1, Err: err}
+		}
+	}
+
+	diagnostic.File = line.File
+	diagnostic.Line = line.Number
+
+	return diagnostic
+}
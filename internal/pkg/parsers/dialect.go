@@ -0,0 +1,146 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrUnknownDialect = errors.New("unknown dialect")
+	ErrMixedDialects  = errors.New("mixed dialects")
+)
+
+// dictionary returns the words of dialect, the default dialect is the empty one.
+func (p *parser) dictionary(dialect string) (map[string]string, error) {
+	if dialect == "" {
+		return p.alienDictionary, nil
+	}
+
+	dictionary, ok := p.dialects[strings.ToLower(dialect)]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownDialect, dialect)
+	}
+
+	return dictionary, nil
+}
+
+// definedDictionary returns the words of the dialect defined by token, the
+// dialect is declared by its first word.
+func (p *parser) definedDictionary(token Token) map[string]string {
+	if token.Text == "" {
+		return p.alienDictionary
+	}
+
+	dialect := strings.ToLower(token.Text)
+	if p.dialects[dialect] == nil {
+		p.dialects[dialect] = map[string]string{}
+	}
+
+	return p.dialects[dialect]
+}
+
+// dictionaryOf resolves the dialect of a number, reporting an unknown dialect
+// at its own column along with the closest known dialect.
+func (p *parser) dictionaryOf(token Token) (map[string]string, error) {
+	dictionary, err := p.dictionary(token.Text)
+	if err != nil {
+		diagnostic := positioned(token, err)
+		diagnostic.Suggestion = suggest(token.Text, keys(p.dialects))
+		return nil, diagnostic
+	}
+
+	return dictionary, nil
+}
+
+// dialectOf returns the first dialect other than dialect with word, the empty
+// one being the default dialect, ok is false when no other dialect knows it.
+func (p *parser) dialectOf(word string, dialect string) (string, bool) {
+	if dialect != "" {
+		if _, ok := p.alienDictionary[word]; ok {
+			return "", true
+		}
+	}
+
+	dialects := keys(p.dialects)
+	slices.Sort(dialects)
+
+	for _, other := range dialects {
+		if _, ok := p.dialects[other][word]; ok && other != dialect {
+			return other, true
+		}
+	}
+
+	return "", false
+}
+
+// isAlienWord reports whether word is known by any dialect, the default one included.
+func (p *parser) isAlienWord(word string) bool {
+	if _, ok := p.alienDictionary[word]; ok {
+		return true
+	}
+
+	_, ok := p.dialectOf(word, "")
+	return ok
+}
+
+// dialectWords returns the words of every dialect along with the default ones.
+func (p *parser) dialectWords() []string {
+	words := keys(p.alienDictionary)
+	for _, dictionary := range p.dialects {
+		words = append(words, keys(dictionary)...)
+	}
+
+	return words
+}
+
+// knownDialects returns a copy of the words of every dialect, nil when none was declared.
+func (p *parser) knownDialects() map[string]map[string]string {
+	if len(p.dialects) == 0 {
+		return nil
+	}
+
+	dialects := make(map[string]map[string]string, len(p.dialects))
+	for dialect, dictionary := range p.dialects {
+		dialects[dialect] = make(map[string]string, len(dictionary))
+		for alien, symbol := range dictionary {
+			dialects[dialect][alien] = symbol
+		}
+	}
+
+	return dialects
+}
+
+// dialectKey returns the name of the dialect token stands for, empty for the default one.
+func dialectKey(token Token) string {
+	return strings.ToLower(token.Text)
+}
+
+// qualified returns word as written in dialect.
+func qualified(dialect string, word string) string {
+	if dialect == "" {
+		return word
+	}
+
+	return strings.ToLower(dialect) + ": " + word
+}
+
+// dialectName names dialect in a message.
+func dialectName(dialect string) string {
+	if dialect == "" {
+		return "the default dialect"
+	}
+
+	return fmt.Sprintf("dialect '%s'", strings.ToLower(dialect))
+}
+
+// isDialectName reports whether the token at idx names a dialect, either in
+// any of the "on <dialect>" scopes or in "<dialect>:".
+func isDialectName(tokens []Token, idx int) bool {
+	if idx > 0 && tokens[idx-1].Is("on") {
+		return true
+	}
+
+	return idx+1 < len(tokens) && tokens[idx+1].Kind == TokenColon
+}
@@ -0,0 +1,115 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+)
+
+// ArithmeticQuestion answers with the value of the expression, both in Arabic
+// and in alien words of the dialect of its numbers, which cannot be mixed. A
+// value out of the range of the numeral system is only answered in Arabic.
+// Every alien number of the expression is an operand, in the order they are
+// written.
+func (p *parser) ArithmeticQuestion(question *ArithmeticQuestion) (Answer, error) {
+	answer := Answer{Kind: QuestionKindArithmetic, Expression: question.Expression.String()}
+
+	value, operands, err := p.evaluate(question.Expression)
+	if err != nil {
+		return answer, err
+	}
+
+	numbers := alienNumbers(question.Expression)
+	dialect := dialectKey(numbers[0].Dialect)
+	for _, number := range numbers[1:] {
+		if other := dialectKey(number.Dialect); other != dialect {
+			return answer, positioned(number.Words[0], fmt.Errorf("%w, '%s' is in %s, not in %s", ErrMixedDialects, number, dialectName(other), dialectName(dialect)))
+		}
+	}
+
+	// known since the numbers were converted
+	dictionary, _ := p.dictionary(dialect)
+
+	// the intermediate results may leave the range, only the result is said in alien words
+	alien, err := p.sayValue(value, dictionary)
+	if err != nil && !errors.Is(err, converters.ErrOutOfRange) {
+		return answer, positioned(firstToken(question.Expression), err)
+	}
+
+	answer.Operands = operands
+	answer.Value = value
+	answer.Alien = alien
+
+	return answer, nil
+}
+
+// evaluate returns the value of expression along with its alien numbers, the
+// values are exact so that no product overflows.
+func (p *parser) evaluate(expression Expression) (rationals.Rational, []Operand, error) {
+	switch expression := expression.(type) {
+	case AlienNumber:
+		operand, err := p.numberOperand(expression)
+		if err != nil {
+			return rationals.Rational{}, nil, err
+		}
+
+		return rationals.FromInt(operand.Value), []Operand{operand}, nil
+	case *GroupExpression:
+		return p.evaluate(expression.Expression)
+	case *BinaryExpression:
+		left, leftOperands, err := p.evaluate(expression.Left)
+		if err != nil {
+			return rationals.Rational{}, nil, err
+		}
+
+		right, rightOperands, err := p.evaluate(expression.Right)
+		if err != nil {
+			return rationals.Rational{}, nil, err
+		}
+
+		operands := append(leftOperands, rightOperands...)
+
+		switch {
+		case expression.Operator.Is("plus"):
+			return left.Add(right), operands, nil
+		case expression.Operator.Is("minus"):
+			return left.Sub(right), operands, nil
+		case expression.Operator.Is("times"):
+			return left.Mul(right), operands, nil
+		default:
+			return rationals.Rational{}, nil, positioned(expression.Operator, ErrMalformedQuestion)
+		}
+	default:
+		return rationals.Rational{}, nil, ErrMalformedQuestion
+	}
+}
+
+// firstToken returns the token expression starts with.
+func firstToken(expression Expression) Token {
+	switch expression := expression.(type) {
+	case AlienNumber:
+		return expression.Words[0]
+	case *GroupExpression:
+		return expression.Open
+	case *BinaryExpression:
+		return firstToken(expression.Left)
+	default:
+		return Token{}
+	}
+}
+
+// alienNumbers returns the alien numbers of expression, in the order they are written.
+func alienNumbers(expression Expression) []AlienNumber {
+	switch expression := expression.(type) {
+	case AlienNumber:
+		return []AlienNumber{expression}
+	case *GroupExpression:
+		return alienNumbers(expression.Expression)
+	case *BinaryExpression:
+		return append(alienNumbers(expression.Left), alienNumbers(expression.Right)...)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,680 @@
+package parsers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// dateLayout is the layout of the dates of the prices, such as 3021-05-01.
+const dateLayout = "2006-01-02"
+
+// keywords end a sequence of alien words.
+var keywords = []string{"is", "how", "much", "many", "credits", "does", "has", "than", "larger", "smaller", "more", "less", "do", "you", "say", "for", "plus", "minus", "times", "on", "as", "of"}
+
+// SyntaxError reports the token that does not fit the grammar.
+type SyntaxError struct {
+	Expected string
+	Found    Token
+}
+
+func (e *SyntaxError) Error() string {
+	found := e.Found.Kind.String()
+	if e.Found.Kind == TokenWord || e.Found.Kind == TokenNumber {
+		found = fmt.Sprintf("'%s'", e.Found.Text)
+	}
+
+	return fmt.Sprintf("expected %s, found %s", e.Expected, found)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrMalformedQuestion
+}
+
+type grammar struct {
+	tokens []Token
+	pos    int
+	// dialect is the dialect of the whole statement, declared by "on <dialect>".
+	dialect Token
+	// date is the date of the whole statement, declared by "on <date>".
+	date Token
+}
+
+// Parse parses a line into a definition or a question, optionally scoped to a
+// dialect whose words are used by every number without a qualifier, and to the
+// date a price is effective from or a question is priced at.
+//
+//	line       = { "on" ( date | word ) } statement
+//	statement  = numerals | currency | commodity | rate | how-much | how-many | how-many-for | exchange | say | does | is
+//	numerals   = "numerals" "are" word
+//	currency   = word "is" symbol
+//	commodity  = quantity "is" decimal word
+//	rate       = decimal word "is" decimal word
+//	how-much   = "how" "much" "is" expression "?"
+//	how-many   = "how" "many" ("credits" | word) "is" quantity [ as-of ] "?"
+//	how-many-for = "how" "many" word "for" decimal "credits" [ as-of ] "?"
+//	exchange   = "how" "many" word ("is" | "for") quantity [ as-of ] "?"
+//	say        = "how" "do" "you" "say" digits "?"
+//	does       = "does" quantity "has" ("more" | "less") "credits" "than" quantity [ as-of ] "?"
+//	is         = "is" number ("larger" | "smaller") "than" number "?"
+//	expression = term { ("plus" | "minus") term }
+//	term       = factor { "times" factor }
+//	factor     = number | "(" expression ")"
+//	quantity   = number word
+//	number     = [ word ":" ] word { word }
+//	decimal    = digits [ "." digits ]
+//	as-of      = "as" "of" date
+//	date       = digits "-" digits "-" digits
+func Parse(line string) (Statement, error) {
+	g := &grammar{tokens: Lex(line)}
+
+	for g.peek().Is("on") {
+		g.next()
+
+		scope, err := g.expectKind(TokenWord)
+		if err != nil {
+			return nil, err
+		}
+
+		if isDate(scope) {
+			g.date = scope
+		} else {
+			g.dialect = scope
+		}
+	}
+
+	return g.parseStatement()
+}
+
+func (g *grammar) peek() Token {
+	return g.tokens[g.pos]
+}
+
+func (g *grammar) next() Token {
+	token := g.tokens[g.pos]
+	if token.Kind != TokenEOF {
+		g.pos++
+	}
+
+	return token
+}
+
+func (g *grammar) expect(keyword string) (Token, error) {
+	token := g.next()
+	if !token.Is(keyword) {
+		return token, &SyntaxError{Expected: fmt.Sprintf("'%s'", keyword), Found: token}
+	}
+
+	return token, nil
+}
+
+func (g *grammar) expectOneOf(keywords ...string) (Token, error) {
+	token := g.next()
+	for _, keyword := range keywords {
+		if token.Is(keyword) {
+			return token, nil
+		}
+	}
+
+	return token, &SyntaxError{Expected: "'" + strings.Join(keywords, "' or '") + "'", Found: token}
+}
+
+func (g *grammar) expectKind(kind TokenKind) (Token, error) {
+	token := g.next()
+	if token.Kind != kind {
+		return token, &SyntaxError{Expected: kind.String(), Found: token}
+	}
+
+	return token, nil
+}
+
+// expectEnd expects the question mark ending a question.
+func (g *grammar) expectEnd() error {
+	_, err := g.expectKind(TokenQuestionMark)
+	if err != nil {
+		return err
+	}
+
+	_, err = g.expectKind(TokenEOF)
+	return err
+}
+
+func (g *grammar) parseStatement() (Statement, error) {
+	token := g.peek()
+
+	switch {
+	case token.Is("numerals") && g.tokens[g.pos+1].Is("are"):
+		return g.parseNumeralsDeclaration()
+	case token.Kind == TokenNumber:
+		return g.parseRateDefinition()
+	case token.Is("how"):
+		return g.parseHowQuestion()
+	case token.Is("does"):
+		return g.parseDoesQuestion()
+	case token.Is("is"):
+		return g.parseIsQuestion()
+	default:
+		return g.parseDefinition()
+	}
+}
+
+func (g *grammar) parseNumeralsDeclaration() (Statement, error) {
+	g.next()
+	g.next()
+
+	system, err := g.expectKind(TokenWord)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expectKind(TokenEOF)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NumeralsDeclaration{Dialect: g.dialect, System: system}, nil
+}
+
+func (g *grammar) parseDefinition() (Statement, error) {
+	dialect := g.parseDialect()
+
+	words := g.parseWords()
+	if len(words) == 0 {
+		return nil, &SyntaxError{Expected: "alien word or question", Found: g.peek()}
+	}
+
+	_, err := g.expect("is")
+	if err != nil {
+		return nil, err
+	}
+
+	value := g.next()
+	if value.Kind == TokenEOF || value.Kind == TokenQuestionMark {
+		return nil, &SyntaxError{Expected: "numeral symbol or credits", Found: value}
+	}
+
+	if g.peek().Kind == TokenEOF {
+		if len(words) != 1 {
+			return nil, &SyntaxError{Expected: "single alien word", Found: words[1]}
+		}
+
+		return &CurrencyDefinition{Dialect: dialect, Word: words[0], Roman: value}, nil
+	}
+
+	quantity, err := quantityOf(dialect, words)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := g.expectKind(TokenWord)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expectKind(TokenEOF)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommodityDefinition{Date: g.date, Quantity: quantity, Credits: value, Currency: currency}, nil
+}
+
+func (g *grammar) parseRateDefinition() (Statement, error) {
+	left, err := g.parseMoney()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expect("is")
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := g.parseMoney()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expectKind(TokenEOF)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RateDefinition{Left: left, Right: right}, nil
+}
+
+func (g *grammar) parseMoney() (Money, error) {
+	amount, err := g.expectKind(TokenNumber)
+	if err != nil {
+		return Money{}, err
+	}
+
+	currency, err := g.expectKind(TokenWord)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (g *grammar) parseHowQuestion() (Statement, error) {
+	g.next()
+
+	kind, err := g.expectOneOf("much", "many", "do")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case kind.Is("much"):
+		return g.parseHowMuchQuestion()
+	case kind.Is("do"):
+		return g.parseSayQuestion()
+	case g.peek().Is("credits"):
+		return g.parseHowManyQuestion()
+	default:
+		return g.parseHowManyForQuestion()
+	}
+}
+
+func (g *grammar) parseHowMuchQuestion() (Statement, error) {
+	_, err := g.expect("is")
+	if err != nil {
+		return nil, err
+	}
+
+	expression, err := g.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.expectEnd()
+	if err != nil {
+		return nil, err
+	}
+
+	// a single alien number keeps the original question
+	if number, ok := expression.(AlienNumber); ok {
+		return &HowMuchQuestion{Number: number}, nil
+	}
+
+	return &ArithmeticQuestion{Expression: expression}, nil
+}
+
+// parseExpression parses the terms added or subtracted from left to right.
+func (g *grammar) parseExpression() (Expression, error) {
+	left, err := g.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for g.peek().Is("plus") || g.peek().Is("minus") {
+		operator := g.next()
+
+		right, err := g.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &BinaryExpression{Left: left, Operator: operator, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseTerm parses the factors multiplied from left to right, binding tighter than plus and minus.
+func (g *grammar) parseTerm() (Expression, error) {
+	left, err := g.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for g.peek().Is("times") {
+		operator := g.next()
+
+		right, err := g.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &BinaryExpression{Left: left, Operator: operator, Right: right}
+	}
+
+	return left, nil
+}
+
+func (g *grammar) parseFactor() (Expression, error) {
+	if g.peek().Kind != TokenLeftParen {
+		return g.parseAlienNumber()
+	}
+
+	open := g.next()
+
+	expression, err := g.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expectKind(TokenRightParen)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GroupExpression{Open: open, Expression: expression}, nil
+}
+
+func (g *grammar) parseHowManyQuestion() (Statement, error) {
+	currency, err := g.expect("credits")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expect("is")
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, err := g.parseQuantity()
+	if err != nil {
+		return nil, err
+	}
+
+	asOf, err := g.parseAsOf()
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.expectEnd()
+	if err != nil {
+		return nil, err
+	}
+
+	return &HowManyQuestion{Currency: currency, Quantity: quantity, AsOf: asOf}, nil
+}
+
+func (g *grammar) parseHowManyForQuestion() (Statement, error) {
+	commodity, err := g.expectKind(TokenWord)
+	if err != nil {
+		return nil, err
+	}
+
+	relation, err := g.expectOneOf("for", "is")
+	if err != nil {
+		return nil, err
+	}
+
+	// credits are a number, a quantity of another commodity starts with an alien word
+	if relation.Is("is") || g.peek().Kind != TokenNumber {
+		return g.parseExchangeQuestion(commodity)
+	}
+
+	credits, err := g.expectKind(TokenNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expect("credits")
+	if err != nil {
+		return nil, err
+	}
+
+	asOf, err := g.parseAsOf()
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.expectEnd()
+	if err != nil {
+		return nil, err
+	}
+
+	return &HowManyForQuestion{Dialect: g.dialect, Commodity: commodity, Credits: credits, AsOf: asOf}, nil
+}
+
+func (g *grammar) parseExchangeQuestion(commodity Token) (Statement, error) {
+	quantity, err := g.parseQuantity()
+	if err != nil {
+		return nil, err
+	}
+
+	asOf, err := g.parseAsOf()
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.expectEnd()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExchangeQuestion{Commodity: commodity, Quantity: quantity, AsOf: asOf}, nil
+}
+
+func (g *grammar) parseSayQuestion() (Statement, error) {
+	_, err := g.expect("you")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expect("say")
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := g.expectKind(TokenNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.expectEnd()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SayQuestion{Dialect: g.dialect, Number: number}, nil
+}
+
+func (g *grammar) parseDoesQuestion() (Statement, error) {
+	g.next()
+
+	left, err := g.parseQuantity()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expect("has")
+	if err != nil {
+		return nil, err
+	}
+
+	relation, err := g.expectOneOf("more", "less")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expect("credits")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expect("than")
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := g.parseQuantity()
+	if err != nil {
+		return nil, err
+	}
+
+	asOf, err := g.parseAsOf()
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.expectEnd()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DoesQuestion{Left: left, Relation: relation, Right: right, AsOf: asOf}, nil
+}
+
+func (g *grammar) parseIsQuestion() (Statement, error) {
+	g.next()
+
+	left, err := g.parseAlienNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	relation, err := g.expectOneOf("larger", "smaller")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.expect("than")
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := g.parseAlienNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.expectEnd()
+	if err != nil {
+		return nil, err
+	}
+
+	return &IsQuestion{Left: left, Relation: relation, Right: right}, nil
+}
+
+// parseAsOf consumes the "as of <date>" a question is priced at, the question
+// is priced at the date of the statement without one.
+func (g *grammar) parseAsOf() (Token, error) {
+	if !g.peek().Is("as") {
+		return g.date, nil
+	}
+
+	g.next()
+
+	_, err := g.expect("of")
+	if err != nil {
+		return Token{}, err
+	}
+
+	date := g.next()
+	if !isDate(date) {
+		return Token{}, &SyntaxError{Expected: "date", Found: date}
+	}
+
+	return date, nil
+}
+
+// parseWords consumes the words up to the next keyword.
+func (g *grammar) parseWords() []Token {
+	words := []Token{}
+	for g.peek().Kind == TokenWord && !isKeyword(g.peek()) {
+		words = append(words, g.next())
+	}
+
+	return words
+}
+
+// parseDialect consumes the "<dialect>:" qualifier of a number, the number is
+// of the dialect of the statement without one.
+func (g *grammar) parseDialect() Token {
+	if g.peek().Kind != TokenWord || g.tokens[g.pos+1].Kind != TokenColon {
+		return g.dialect
+	}
+
+	dialect := g.next()
+	g.next()
+
+	return dialect
+}
+
+func (g *grammar) parseAlienNumber() (AlienNumber, error) {
+	dialect := g.parseDialect()
+
+	words := g.parseWords()
+	if len(words) == 0 {
+		return AlienNumber{}, &SyntaxError{Expected: "alien word", Found: g.peek()}
+	}
+
+	return AlienNumber{Dialect: dialect, Words: words}, nil
+}
+
+func (g *grammar) parseQuantity() (Quantity, error) {
+	dialect := g.parseDialect()
+
+	words := g.parseWords()
+	if len(words) < 2 {
+		return Quantity{}, &SyntaxError{Expected: "alien number followed by a commodity", Found: g.peek()}
+	}
+
+	return quantityOf(dialect, words)
+}
+
+func quantityOf(dialect Token, words []Token) (Quantity, error) {
+	if len(words) < 2 {
+		return Quantity{}, &SyntaxError{Expected: "alien number followed by a commodity", Found: words[len(words)-1]}
+	}
+
+	return Quantity{
+		Number:    AlienNumber{Dialect: dialect, Words: words[:len(words)-1]},
+		Commodity: words[len(words)-1],
+	}, nil
+}
+
+// isDate reports whether token is a date such as 3021-05-01.
+func isDate(token Token) bool {
+	if token.Kind != TokenWord {
+		return false
+	}
+
+	_, err := time.Parse(dateLayout, token.Text)
+	return err == nil
+}
+
+func isKeyword(token Token) bool {
+	return slices.ContainsFunc(keywords, token.Is)
+}
+
+// isOperator reports whether token can only appear in an arithmetic expression.
+func isOperator(token Token) bool {
+	return token.Is("plus") || token.Is("minus") || token.Is("times") || token.Kind == TokenLeftParen || token.Kind == TokenRightParen
+}
+
+// questionKind guesses the kind of a question from its leading tokens, even when it is malformed.
+func questionKind(tokens []Token) QuestionKind {
+	// the dialect of the question does not change its kind
+	if len(tokens) > 2 && tokens[0].Is("on") {
+		tokens = tokens[2:]
+	}
+
+	switch {
+	case len(tokens) > 1 && tokens[0].Is("how") && tokens[1].Is("much") && slices.ContainsFunc(tokens, isOperator):
+		return QuestionKindArithmetic
+	case len(tokens) > 1 && tokens[0].Is("how") && tokens[1].Is("much"):
+		return QuestionKindHowMuch
+	case len(tokens) > 4 && tokens[0].Is("how") && tokens[1].Is("many") && !tokens[2].Is("credits") &&
+		(tokens[3].Is("is") || (tokens[3].Is("for") && tokens[4].Kind != TokenNumber)):
+		return QuestionKindExchange
+	case len(tokens) > 2 && tokens[0].Is("how") && tokens[1].Is("many") && !tokens[2].Is("credits"):
+		return QuestionKindHowManyFor
+	case len(tokens) > 1 && tokens[0].Is("how") && tokens[1].Is("many"):
+		return QuestionKindHowMany
+	case len(tokens) > 1 && tokens[0].Is("how") && tokens[1].Is("do"):
+		return QuestionKindSay
+	case tokens[0].Is("does"):
+		return QuestionKindDoes
+	case tokens[0].Is("is"):
+		return QuestionKindIs
+	default:
+		return QuestionKindUnknown
+	}
+}
@@ -0,0 +1,333 @@
+package parsers_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/go-test/deep"
+)
+
+func word(text string, column int) parsers.Token {
+	return parsers.Token{Kind: parsers.TokenWord, Text: text, Column: column}
+}
+
+func TestParse(t *testing.T) {
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result parsers.Statement
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when currency is defined should return currency definition",
+			args: args{
+				param: "glob is I",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.CurrencyDefinition{Word: word("glob", 1), Roman: word("I", 9)},
+			},
+		},
+		{
+			name: "when currency is defined in a dialect should return currency definition of the dialect",
+			args: args{
+				param: "on Vega glob is X",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.CurrencyDefinition{Dialect: word("Vega", 4), Word: word("glob", 9), Roman: word("X", 17)},
+			},
+		},
+		{
+			name: "when commodity is defined should return commodity definition",
+			args: args{
+				param: "glob  prok Gold is 57800 Credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.CommodityDefinition{
+					Quantity: parsers.Quantity{
+						Number:    parsers.AlienNumber{Words: []parsers.Token{word("glob", 1), word("prok", 7)}},
+						Commodity: word("Gold", 12),
+					},
+					Credits:  parsers.Token{Kind: parsers.TokenNumber, Text: "57800", Column: 20},
+					Currency: word("Credits", 26),
+				},
+			},
+		},
+		{
+			name: "when commodity is defined on a date should return dated commodity definition",
+			args: args{
+				param: "on 3021-05-01 glob Gold is 14450 Credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.CommodityDefinition{
+					Date: word("3021-05-01", 4),
+					Quantity: parsers.Quantity{
+						Number:    parsers.AlienNumber{Words: []parsers.Token{word("glob", 15)}},
+						Commodity: word("Gold", 20),
+					},
+					Credits:  parsers.Token{Kind: parsers.TokenNumber, Text: "14450", Column: 28},
+					Currency: word("Credits", 34),
+				},
+			},
+		},
+		{
+			name: "when rate is defined should return rate definition",
+			args: args{
+				param: "1 Credits is 3 Zorbs",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.RateDefinition{
+					Left:  parsers.Money{Amount: parsers.Token{Kind: parsers.TokenNumber, Text: "1", Column: 1}, Currency: word("Credits", 3)},
+					Right: parsers.Money{Amount: parsers.Token{Kind: parsers.TokenNumber, Text: "3", Column: 14}, Currency: word("Zorbs", 16)},
+				},
+			},
+		},
+		{
+			name: "when how many question has attached question mark should return how many question",
+			args: args{
+				param: "how many Credits is glob Iron?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.HowManyQuestion{
+					Currency: word("Credits", 10),
+					Quantity: parsers.Quantity{
+						Number:    parsers.AlienNumber{Words: []parsers.Token{word("glob", 21)}},
+						Commodity: word("Iron", 26),
+					},
+				},
+			},
+		},
+		{
+			name: "when is question is valid should return is question",
+			args: args{
+				param: "is glob larger than prok ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.IsQuestion{
+					Left:     parsers.AlienNumber{Words: []parsers.Token{word("glob", 4)}},
+					Relation: word("larger", 9),
+					Right:    parsers.AlienNumber{Words: []parsers.Token{word("prok", 21)}},
+				},
+			},
+		},
+		{
+			name: "when say question is valid should return say question",
+			args: args{
+				param: "how do you say 42 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.SayQuestion{Number: parsers.Token{Kind: parsers.TokenNumber, Text: "42", Column: 16}},
+			},
+		},
+		{
+			name: "when how many for question is valid should return how many for question",
+			args: args{
+				param: "how many Silver for 68 Credits ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.HowManyForQuestion{
+					Commodity: word("Silver", 10),
+					Credits:   parsers.Token{Kind: parsers.TokenNumber, Text: "68", Column: 21},
+				},
+			},
+		},
+		{
+			name: "when exchange question names a quantity should return exchange question",
+			args: args{
+				param: "how many Iron for pish Silver ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.ExchangeQuestion{
+					Commodity: word("Iron", 10),
+					Quantity: parsers.Quantity{
+						Number:    parsers.AlienNumber{Words: []parsers.Token{word("pish", 19)}},
+						Commodity: word("Silver", 24),
+					},
+				},
+			},
+		},
+		{
+			name: "when arithmetic question mixes operators should bind times tighter",
+			args: args{
+				param: "how much is tegj minus pish times (glob plus glob) ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.ArithmeticQuestion{
+					Expression: &parsers.BinaryExpression{
+						Left:     parsers.AlienNumber{Words: []parsers.Token{word("tegj", 13)}},
+						Operator: word("minus", 18),
+						Right: &parsers.BinaryExpression{
+							Left:     parsers.AlienNumber{Words: []parsers.Token{word("pish", 24)}},
+							Operator: word("times", 29),
+							Right: &parsers.GroupExpression{
+								Open: parsers.Token{Kind: parsers.TokenLeftParen, Text: "(", Column: 35},
+								Expression: &parsers.BinaryExpression{
+									Left:     parsers.AlienNumber{Words: []parsers.Token{word("glob", 36)}},
+									Operator: word("plus", 41),
+									Right:    parsers.AlienNumber{Words: []parsers.Token{word("glob", 46)}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "when number is qualified should return number of the dialect",
+			args: args{
+				param: "on Orion is Vega: glob larger than glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.IsQuestion{
+					Left:     parsers.AlienNumber{Dialect: word("Vega", 13), Words: []parsers.Token{word("glob", 19)}},
+					Relation: word("larger", 24),
+					Right:    parsers.AlienNumber{Dialect: word("Orion", 4), Words: []parsers.Token{word("glob", 36)}},
+				},
+			},
+		},
+		{
+			name: "when dialect qualifier has no word should return error",
+			args: args{
+				param: "how much is Vega: ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("expected alien word, found '?'"),
+			},
+		},
+		{
+			name: "when how many question is as of a date should return dated how many question",
+			args: args{
+				param: "how many Credits is glob Iron as of 3021-05-01 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: &parsers.HowManyQuestion{
+					Currency: word("Credits", 10),
+					Quantity: parsers.Quantity{
+						Number:    parsers.AlienNumber{Words: []parsers.Token{word("glob", 21)}},
+						Commodity: word("Iron", 26),
+					},
+					AsOf: word("3021-05-01", 37),
+				},
+			},
+		},
+		{
+			name: "when as of is not followed by a date should return error",
+			args: args{
+				param: "how many Credits is glob Iron as of yesterday ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("expected date, found 'yesterday'"),
+			},
+		},
+		{
+			name: "when arithmetic question has no right operand should return error",
+			args: args{
+				param: "how much is glob plus ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("expected alien word, found '?'"),
+			},
+		},
+		{
+			name: "when say question has no number should return error",
+			args: args{
+				param: "how do you say glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("expected number, found 'glob'"),
+			},
+		},
+		{
+			name: "when currency value is missing should return error",
+			args: args{
+				param: "glob is",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("expected numeral symbol or credits, found end of line"),
+			},
+		},
+		{
+			name: "when is appears twice should return error",
+			args: args{
+				param: "how much is is ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("expected alien word, found 'is'"),
+			},
+		},
+		{
+			name: "when question mark is missing should return error",
+			args: args{
+				param: "how much is glob",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("expected '?', found end of line"),
+			},
+		},
+		{
+			name: "when commodity is missing should return error",
+			args: args{
+				param: "does glob has more credits than glob gold ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("expected alien number followed by a commodity, found 'has'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, err := parsers.Parse(tc.args.param)
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+
+				if !errors.Is(err, parsers.ErrMalformedQuestion) {
+					t.Errorf("got error not wrapping malformed question: %v", err)
+				}
+				return
+			}
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
@@ -0,0 +1,100 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
+)
+
+var ErrNoPrice = errors.New("no price")
+
+// addPrice learns that a unit of commodity is worth price from date on.
+func (p *parser) addPrice(commodity string, date string, price rationals.Rational) {
+	if p.history[commodity] == nil {
+		p.history[commodity] = map[string]rationals.Rational{}
+	}
+
+	p.history[commodity][date] = price
+}
+
+// priceOf returns the price of a unit of commodity effective at date, the
+// latest price when date is empty. A price without date is effective before
+// any dated one.
+func (p *parser) priceOf(commodity string, date string) (rationals.Rational, bool) {
+	dates := keys(p.history[commodity])
+	slices.Sort(dates)
+
+	for idx := len(dates) - 1; idx >= 0; idx-- {
+		if date == "" || dates[idx] <= date {
+			return p.history[commodity][dates[idx]], true
+		}
+	}
+
+	price, ok := p.metalValue[commodity]
+	return price, ok
+}
+
+// isPriced reports whether commodity has a price, at any date.
+func (p *parser) isPriced(commodity string) bool {
+	_, ok := p.priceOf(commodity, "")
+	return ok
+}
+
+// pricedCommodities returns every commodity with a price, at any date.
+func (p *parser) pricedCommodities() []string {
+	commodities := keys(p.metalValue)
+	for commodity := range p.history {
+		if _, ok := p.metalValue[commodity]; !ok {
+			commodities = append(commodities, commodity)
+		}
+	}
+
+	return commodities
+}
+
+// knownHistory returns a copy of the dated prices, nil when there is none.
+func (p *parser) knownHistory() map[string]map[string]rationals.Rational {
+	if len(p.history) == 0 {
+		return nil
+	}
+
+	history := make(map[string]map[string]rationals.Rational, len(p.history))
+	for commodity, prices := range p.history {
+		history[commodity] = make(map[string]rationals.Rational, len(prices))
+		for date, price := range prices {
+			history[commodity][date] = price
+		}
+	}
+
+	return history
+}
+
+// validHistory reports the first date of history that is not a date or the
+// first price that is not positive.
+func validHistory(history map[string]map[string]rationals.Rational) error {
+	for commodity, prices := range history {
+		for date, price := range prices {
+			if _, err := time.Parse(dateLayout, date); err != nil {
+				return fmt.Errorf("%w: price of '%s' is dated '%s'", storages.ErrInvalidKnowledgeBase, commodity, date)
+			}
+
+			if price.Sign() <= 0 {
+				return fmt.Errorf("%w: price of '%s' on '%s' is %s", storages.ErrInvalidKnowledgeBase, commodity, date, price)
+			}
+		}
+	}
+
+	return nil
+}
+
+func priceKey(commodity string, date string) string {
+	if date == "" {
+		return commodity
+	}
+
+	return commodity + " " + date
+}
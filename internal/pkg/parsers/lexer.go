@@ -0,0 +1,120 @@
+package parsers
+
+import (
+	"strings"
+	"unicode"
+)
+
+type TokenKind int
+
+const (
+	TokenWord TokenKind = iota
+	TokenNumber
+	TokenQuestionMark
+	TokenLeftParen
+	TokenRightParen
+	TokenColon
+	TokenEOF
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenWord:
+		return "word"
+	case TokenNumber:
+		return "number"
+	case TokenQuestionMark:
+		return "'?'"
+	case TokenLeftParen:
+		return "'('"
+	case TokenRightParen:
+		return "')'"
+	case TokenColon:
+		return "':'"
+	default:
+		return "end of line"
+	}
+}
+
+// Token is a lexeme of a line, Column is the 1-based position of its first character.
+type Token struct {
+	Kind   TokenKind
+	Text   string
+	Column int
+}
+
+// Is reports whether the token is the given keyword, ignoring case.
+func (t Token) Is(keyword string) bool {
+	return t.Kind == TokenWord && strings.EqualFold(t.Text, keyword)
+}
+
+// Lex splits line into tokens separated by any amount of white space, a
+// question mark, parentheses and colons are always tokens of their own. The
+// last token is TokenEOF.
+func Lex(line string) []Token {
+	tokens := []Token{}
+	runes := []rune(line)
+
+	for idx := 0; idx < len(runes); {
+		r := runes[idx]
+
+		switch {
+		case unicode.IsSpace(r):
+			idx++
+		case punctuation(r) != TokenWord:
+			tokens = append(tokens, Token{Kind: punctuation(r), Text: string(r), Column: idx + 1})
+			idx++
+		default:
+			start := idx
+			for idx < len(runes) && !unicode.IsSpace(runes[idx]) && punctuation(runes[idx]) == TokenWord {
+				idx++
+			}
+
+			text := string(runes[start:idx])
+			tokens = append(tokens, Token{Kind: wordKind(text), Text: text, Column: start + 1})
+		}
+	}
+
+	return append(tokens, Token{Kind: TokenEOF, Column: len(runes) + 1})
+}
+
+// punctuation returns the kind of the single character token r, TokenWord when
+// r is part of a word.
+func punctuation(r rune) TokenKind {
+	switch r {
+	case '?':
+		return TokenQuestionMark
+	case '(':
+		return TokenLeftParen
+	case ')':
+		return TokenRightParen
+	case ':':
+		return TokenColon
+	default:
+		return TokenWord
+	}
+}
+
+// wordKind returns TokenNumber for whole and decimal numbers such as "42" or "12.5".
+func wordKind(text string) TokenKind {
+	digits, decimals, found := strings.Cut(text, ".")
+	if !isDigits(digits) || (found && !isDigits(decimals)) {
+		return TokenWord
+	}
+
+	return TokenNumber
+}
+
+func isDigits(text string) bool {
+	if text == "" {
+		return false
+	}
+
+	for _, r := range text {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,143 @@
+package parsers_test
+
+import (
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/go-test/deep"
+)
+
+func TestLex(t *testing.T) {
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result []parsers.Token
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when words are separated by spaces and tabs should return positioned tokens",
+			args: args{
+				param: "glob  is\tI",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Token{
+					{Kind: parsers.TokenWord, Text: "glob", Column: 1},
+					{Kind: parsers.TokenWord, Text: "is", Column: 7},
+					{Kind: parsers.TokenWord, Text: "I", Column: 10},
+					{Kind: parsers.TokenEOF, Column: 11},
+				},
+			},
+		},
+		{
+			name: "when question mark is attached should split it",
+			args: args{
+				param: "Iron?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Token{
+					{Kind: parsers.TokenWord, Text: "Iron", Column: 1},
+					{Kind: parsers.TokenQuestionMark, Text: "?", Column: 5},
+					{Kind: parsers.TokenEOF, Column: 6},
+				},
+			},
+		},
+		{
+			name: "when parentheses are attached should split them",
+			args: args{
+				param: "(glob plus prok)",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Token{
+					{Kind: parsers.TokenLeftParen, Text: "(", Column: 1},
+					{Kind: parsers.TokenWord, Text: "glob", Column: 2},
+					{Kind: parsers.TokenWord, Text: "plus", Column: 7},
+					{Kind: parsers.TokenWord, Text: "prok", Column: 12},
+					{Kind: parsers.TokenRightParen, Text: ")", Column: 16},
+					{Kind: parsers.TokenEOF, Column: 17},
+				},
+			},
+		},
+		{
+			name: "when colon is attached should split it",
+			args: args{
+				param: "Vega: glob",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Token{
+					{Kind: parsers.TokenWord, Text: "Vega", Column: 1},
+					{Kind: parsers.TokenColon, Text: ":", Column: 5},
+					{Kind: parsers.TokenWord, Text: "glob", Column: 7},
+					{Kind: parsers.TokenEOF, Column: 11},
+				},
+			},
+		},
+		{
+			name: "when word is made of digits should return a number",
+			args: args{
+				param: "57800 57800a",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Token{
+					{Kind: parsers.TokenNumber, Text: "57800", Column: 1},
+					{Kind: parsers.TokenWord, Text: "57800a", Column: 7},
+					{Kind: parsers.TokenEOF, Column: 13},
+				},
+			},
+		},
+		{
+			name: "when word is a decimal number should return a number",
+			args: args{
+				param: "12.5 12. .5",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Token{
+					{Kind: parsers.TokenNumber, Text: "12.5", Column: 1},
+					{Kind: parsers.TokenWord, Text: "12.", Column: 6},
+					{Kind: parsers.TokenWord, Text: ".5", Column: 10},
+					{Kind: parsers.TokenEOF, Column: 12},
+				},
+			},
+		},
+		{
+			name: "when line is empty should return only end of line",
+			args: args{
+				param: "",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Token{
+					{Kind: parsers.TokenEOF, Column: 1},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result := parsers.Lex(tc.args.param)
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
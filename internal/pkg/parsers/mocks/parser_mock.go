@@ -5,8 +5,12 @@
 package mock_parsers
 
 import (
+	context "context"
 	reflect "reflect"
 
+	parsers "github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	rationals "github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	storages "github.com/arieffian/roman-alien-currency/internal/pkg/storages"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -33,18 +37,62 @@ func (m *MockParserService) EXPECT() *MockParserServiceMockRecorder {
 	return m.recorder
 }
 
-// FixTypo mocks base method.
-func (m *MockParserService) FixTypo(param string) string {
+// AlienDictionary mocks base method.
+func (m *MockParserService) AlienDictionary() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AlienDictionary")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// AlienDictionary indicates an expected call of AlienDictionary.
+func (mr *MockParserServiceMockRecorder) AlienDictionary() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AlienDictionary", reflect.TypeOf((*MockParserService)(nil).AlienDictionary))
+}
+
+// ArabicToAlien mocks base method.
+func (m *MockParserService) ArabicToAlien(number int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArabicToAlien", number)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArabicToAlien indicates an expected call of ArabicToAlien.
+func (mr *MockParserServiceMockRecorder) ArabicToAlien(number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArabicToAlien", reflect.TypeOf((*MockParserService)(nil).ArabicToAlien), number)
+}
+
+// Conflicts mocks base method.
+func (m *MockParserService) Conflicts() []parsers.Conflict {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FixTypo", param)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Conflicts")
+	ret0, _ := ret[0].([]parsers.Conflict)
 	return ret0
 }
 
+// Conflicts indicates an expected call of Conflicts.
+func (mr *MockParserServiceMockRecorder) Conflicts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conflicts", reflect.TypeOf((*MockParserService)(nil).Conflicts))
+}
+
+// FixTypo mocks base method.
+func (m *MockParserService) FixTypo(line parsers.Line) (parsers.Line, []parsers.Correction) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FixTypo", line)
+	ret0, _ := ret[0].(parsers.Line)
+	ret1, _ := ret[1].([]parsers.Correction)
+	return ret0, ret1
+}
+
 // FixTypo indicates an expected call of FixTypo.
-func (mr *MockParserServiceMockRecorder) FixTypo(param interface{}) *gomock.Call {
+func (mr *MockParserServiceMockRecorder) FixTypo(line interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FixTypo", reflect.TypeOf((*MockParserService)(nil).FixTypo), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FixTypo", reflect.TypeOf((*MockParserService)(nil).FixTypo), line)
 }
 
 // GetCurrencyValue mocks base method.
@@ -62,46 +110,101 @@ func (mr *MockParserServiceMockRecorder) GetCurrencyValue(param interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrencyValue", reflect.TypeOf((*MockParserService)(nil).GetCurrencyValue), param)
 }
 
+// Knowledge mocks base method.
+func (m *MockParserService) Knowledge() storages.KnowledgeBase {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Knowledge")
+	ret0, _ := ret[0].(storages.KnowledgeBase)
+	return ret0
+}
+
+// Knowledge indicates an expected call of Knowledge.
+func (mr *MockParserServiceMockRecorder) Knowledge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Knowledge", reflect.TypeOf((*MockParserService)(nil).Knowledge))
+}
+
+// MetalValue mocks base method.
+func (m *MockParserService) MetalValue() map[string]rationals.Rational {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MetalValue")
+	ret0, _ := ret[0].(map[string]rationals.Rational)
+	return ret0
+}
+
+// MetalValue indicates an expected call of MetalValue.
+func (mr *MockParserServiceMockRecorder) MetalValue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MetalValue", reflect.TypeOf((*MockParserService)(nil).MetalValue))
+}
+
 // ParseCurrency mocks base method.
-func (m *MockParserService) ParseCurrency(param []string) bool {
+func (m *MockParserService) ParseCurrency(ctx context.Context, line parsers.Line) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ParseCurrency", param)
+	ret := m.ctrl.Call(m, "ParseCurrency", ctx, line)
 	ret0, _ := ret[0].(bool)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ParseCurrency indicates an expected call of ParseCurrency.
-func (mr *MockParserServiceMockRecorder) ParseCurrency(param interface{}) *gomock.Call {
+func (mr *MockParserServiceMockRecorder) ParseCurrency(ctx, line interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseCurrency", reflect.TypeOf((*MockParserService)(nil).ParseCurrency), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseCurrency", reflect.TypeOf((*MockParserService)(nil).ParseCurrency), ctx, line)
 }
 
 // ParseMetal mocks base method.
-func (m *MockParserService) ParseMetal(param []string) (bool, error) {
+func (m *MockParserService) ParseMetal(ctx context.Context, line parsers.Line) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ParseMetal", param)
+	ret := m.ctrl.Call(m, "ParseMetal", ctx, line)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ParseMetal indicates an expected call of ParseMetal.
-func (mr *MockParserServiceMockRecorder) ParseMetal(param interface{}) *gomock.Call {
+func (mr *MockParserServiceMockRecorder) ParseMetal(ctx, line interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseMetal", reflect.TypeOf((*MockParserService)(nil).ParseMetal), param)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseMetal", reflect.TypeOf((*MockParserService)(nil).ParseMetal), ctx, line)
 }
 
 // ProcessQuestion mocks base method.
-func (m *MockParserService) ProcessQuestion(questions []string) ([]string, error) {
+func (m *MockParserService) ProcessQuestion(ctx context.Context, questions []parsers.Line) ([]parsers.Answer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessQuestion", questions)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "ProcessQuestion", ctx, questions)
+	ret0, _ := ret[0].([]parsers.Answer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ProcessQuestion indicates an expected call of ProcessQuestion.
-func (mr *MockParserServiceMockRecorder) ProcessQuestion(questions interface{}) *gomock.Call {
+func (mr *MockParserServiceMockRecorder) ProcessQuestion(ctx, questions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessQuestion", reflect.TypeOf((*MockParserService)(nil).ProcessQuestion), ctx, questions)
+}
+
+// Reset mocks base method.
+func (m *MockParserService) Reset() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reset")
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockParserServiceMockRecorder) Reset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockParserService)(nil).Reset))
+}
+
+// Restore mocks base method.
+func (m *MockParserService) Restore(knowledge storages.KnowledgeBase) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", knowledge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockParserServiceMockRecorder) Restore(knowledge interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessQuestion", reflect.TypeOf((*MockParserService)(nil).ProcessQuestion), questions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockParserService)(nil).Restore), knowledge)
 }
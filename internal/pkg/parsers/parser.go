@@ -1,35 +1,74 @@
 package parsers
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"math"
 	"slices"
 	"strconv"
 	"strings"
 
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
-
 	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
 )
 
 type ParserService interface {
-	ParseCurrency(param []string) bool
+	ParseCurrency(ctx context.Context, line Line) (bool, error)
 	GetCurrencyValue(param []string) (int, error)
-	ParseMetal(param []string) (bool, error)
-	ProcessQuestion(questions []string) ([]string, error)
-	FixTypo(param string) string
+	ArabicToAlien(number int) ([]string, error)
+	ParseMetal(ctx context.Context, line Line) (bool, error)
+	ProcessQuestion(ctx context.Context, questions []Line) ([]Answer, error)
+	FixTypo(line Line) (Line, []Correction)
+	AlienDictionary() map[string]string
+	MetalValue() map[string]rationals.Rational
+	Knowledge() storages.KnowledgeBase
+	Restore(knowledge storages.KnowledgeBase) error
+	Conflicts() []Conflict
+	Reset()
 }
 
 type parser struct {
-	alienDictionary map[string]string
-	metalValue      map[string]float64
-	converter       converters.ConverterService
+	alienDictionary         map[string]string
+	metalValue              map[string]rationals.Rational
+	converter               converters.ConverterService
+	commodityAllowList      []string
+	commodityDenyList       []string
+	numerals                converters.NumeralRegistryService
+	defaultNumeralSystem    converters.NumeralSystem
+	numeralSystem           converters.NumeralSystem
+	strictTypos             bool
+	minCorrectionConfidence float64
+	conflictPolicy          ConflictPolicy
+	rounding                rationals.Rounding
+	// dialects are the words of every dialect but the default one, alienDictionary.
+	dialects map[string]map[string]string
+	// history[commodity][date] is the price of a unit of commodity from date on,
+	// metalValue keeps the prices without date.
+	history map[string]map[string]rationals.Rational
+	// rates[from][to] is the number of units of to a unit of from is worth.
+	rates map[string]map[string]rationals.Rational
+	// wordSources, priceSources and rateSources are the definitions of the
+	// learned words, keyed by their qualified word, prices, keyed by their
+	// commodity and date, and rates.
+	wordSources  map[string]Source
+	priceSources map[string]Source
+	rateSources  map[string]Source
+	conflicts    []Conflict
 }
 
 var (
-	romanSymbols     = []string{"i", "v", "x", "l", "c", "d", "m"}
-	metalSymbols     = []string{"gold", "silver", "iron"}
+	ErrUnknownCommodity    = errors.New("unknown commodity")
+	ErrMalformedQuestion   = errors.New("malformed question")
+	ErrCommodityNotAllowed = errors.New("not allowed")
+	// ErrScopedNumerals and ErrNumeralsRedeclared reject a numeral system
+	// declared for a single dialect or once alien words were learned, the
+	// numeral system is shared by every word.
+	ErrScopedNumerals     = errors.New("numeral system cannot be declared on dialect")
+	ErrNumeralsRedeclared = errors.New("numeral system cannot change once alien words are learned")
+)
+
+var (
 	reservedKeywords = []string{"is", "how", "much", "many", "credits", "does", "than", "larger", "smaller", "has", "I", "V", "X", "L", "C", "D", "M", "?"}
 )
 
@@ -38,269 +77,836 @@ var _ ParserService = (*parser)(nil)
 type NewParserParams struct {
 	Converter       converters.ConverterService
 	AlienDictionary map[string]string
-	MetalValue      map[string]float64
+	MetalValue      map[string]rationals.Rational
+	// CommodityAllowList restricts the commodities that can be learned, any commodity is allowed when empty.
+	CommodityAllowList []string
+	// CommodityDenyList lists the commodities that can never be learned.
+	CommodityDenyList []string
+	// Numerals are the systems a dictionary can declare with "numerals are <system>",
+	// defaults to the roman, attic, babylonian and mayan systems.
+	Numerals converters.NumeralRegistryService
+	// NumeralSystem is the system of the alien words until another one is declared, defaults to roman.
+	NumeralSystem converters.NumeralSystem
+	// StrictTypos disables FixTypo, unknown words are reported instead of being corrected.
+	StrictTypos bool
+	// MinCorrectionConfidence is the confidence, between 0 and 1, a correction needs to be applied.
+	MinCorrectionConfidence float64
+	// ConflictPolicy resolves the definitions contradicting an earlier one, defaults to ConflictWarn.
+	ConflictPolicy ConflictPolicy
+	// Rounding writes the prices and rates of the conflicts, rationals.DefaultRounding when its mode is not set.
+	Rounding rationals.Rounding
 }
 
 func NewParser(p NewParserParams) *parser {
 
+	minCorrectionConfidence := p.MinCorrectionConfidence
+	if minCorrectionConfidence == 0 {
+		minCorrectionConfidence = defaultMinCorrectionConfidence
+	}
+
+	numerals := p.Numerals
+	if numerals == nil {
+		numerals = converters.NewNumeralRegistry(converters.NewNumeralRegistryParams{
+			Converter: p.Converter,
+		})
+	}
+
+	numeralSystem := p.NumeralSystem
+	if numeralSystem == nil {
+		numeralSystem = converters.NewRoman(p.Converter)
+	}
+
+	conflictPolicy := p.ConflictPolicy
+	if conflictPolicy == "" {
+		conflictPolicy = ConflictWarn
+	}
+
+	rounding := p.Rounding
+	if rounding.Mode == "" {
+		rounding = rationals.DefaultRounding
+	}
+
 	return &parser{
-		alienDictionary: p.AlienDictionary,
-		metalValue:      p.MetalValue,
-		converter:       p.Converter,
+		alienDictionary:         p.AlienDictionary,
+		metalValue:              p.MetalValue,
+		converter:               p.Converter,
+		commodityAllowList:      lowerAll(p.CommodityAllowList),
+		commodityDenyList:       lowerAll(p.CommodityDenyList),
+		numerals:                numerals,
+		defaultNumeralSystem:    numeralSystem,
+		numeralSystem:           numeralSystem,
+		strictTypos:             p.StrictTypos,
+		minCorrectionConfidence: minCorrectionConfidence,
+		conflictPolicy:          conflictPolicy,
+		// a conflict is a sentence, its prices are written as decimal numbers
+		rounding:     rounding.Decimal(),
+		dialects:     map[string]map[string]string{},
+		history:      map[string]map[string]rationals.Rational{},
+		rates:        map[string]map[string]rationals.Rational{},
+		wordSources:  map[string]Source{},
+		priceSources: map[string]Source{},
+		rateSources:  map[string]Source{},
+	}
+}
+
+// AlienDictionary returns a copy of the learned alien words of the default dialect.
+func (p *parser) AlienDictionary() map[string]string {
+	dictionary := make(map[string]string, len(p.alienDictionary))
+	for alien, roman := range p.alienDictionary {
+		dictionary[alien] = roman
+	}
+
+	return dictionary
+}
+
+// MetalValue returns a copy of the current metal prices, the latest dated
+// price of a commodity replaces its price without date.
+func (p *parser) MetalValue() map[string]rationals.Rational {
+	commodities := p.pricedCommodities()
+
+	metalValue := make(map[string]rationals.Rational, len(commodities))
+	for _, metal := range commodities {
+		metalValue[metal], _ = p.priceOf(metal, "")
+	}
+
+	return metalValue
+}
+
+// Knowledge returns a copy of everything the parser learned, Numerals is only
+// set when a numeral system other than the default one was declared.
+func (p *parser) Knowledge() storages.KnowledgeBase {
+	prices := make(map[string]rationals.Rational, len(p.metalValue))
+	for metal, value := range p.metalValue {
+		prices[metal] = value
+	}
+
+	knowledge := storages.KnowledgeBase{
+		Dictionary: p.AlienDictionary(),
+		Prices:     prices,
+		History:    p.knownHistory(),
+		Rates:      p.knownRates(),
+		Dialects:   p.knownDialects(),
 	}
+
+	if p.numeralSystem.Name() != p.defaultNumeralSystem.Name() {
+		knowledge.Numerals = p.numeralSystem.Name()
+	}
+
+	return knowledge
 }
 
-func (p *parser) ParseCurrency(param []string) bool {
-	isIdx := slices.Index(param, "is")
-	found := false
-	if isIdx != -1 {
-		if slices.Index(romanSymbols, param[isIdx+1]) != -1 {
-			p.alienDictionary[param[isIdx-1]] = param[isIdx+1]
-			found = true
+// Restore replaces everything the parser learned by knowledge, nothing is
+// replaced when its numeral system is unknown, one of its words is given a
+// symbol out of the numeral system, one of its rates is invalid or one of its
+// prices is not positive or not dated by a date.
+func (p *parser) Restore(knowledge storages.KnowledgeBase) error {
+	system := p.defaultNumeralSystem
+	if knowledge.Numerals != "" {
+		var err error
+		system, err = p.numerals.Get(knowledge.Numerals)
+		if err != nil {
+			return err
 		}
 	}
 
-	return found
+	if err := validDictionary(system, knowledge.Dictionary); err != nil {
+		return err
+	}
+
+	for _, dictionary := range knowledge.Dialects {
+		if err := validDictionary(system, dictionary); err != nil {
+			return err
+		}
+	}
+
+	for commodity, price := range knowledge.Prices {
+		if price.Sign() <= 0 {
+			return fmt.Errorf("%w: price of '%s' is %s", storages.ErrInvalidKnowledgeBase, commodity, price)
+		}
+	}
+
+	for _, rate := range knowledge.Rates {
+		if rate.Rate.Sign() <= 0 || strings.EqualFold(rate.From, rate.To) {
+			return fmt.Errorf("%w: rate of '%s' to '%s' is %s", storages.ErrInvalidKnowledgeBase, rate.From, rate.To, rate.Rate)
+		}
+	}
+
+	if err := validHistory(knowledge.History); err != nil {
+		return err
+	}
+
+	p.Reset()
+	p.numeralSystem = system
+
+	for alien, symbol := range knowledge.Dictionary {
+		p.alienDictionary[alien] = symbol
+	}
+
+	for metal, value := range knowledge.Prices {
+		p.metalValue[metal] = value
+	}
+
+	for metal, prices := range knowledge.History {
+		for date, value := range prices {
+			p.addPrice(metal, date, value)
+		}
+	}
+
+	for _, rate := range knowledge.Rates {
+		p.addRate(strings.ToLower(rate.From), strings.ToLower(rate.To), rate.Rate, Source{})
+	}
+
+	for dialect, dictionary := range knowledge.Dialects {
+		words := p.definedDictionary(Token{Text: dialect})
+		for alien, symbol := range dictionary {
+			words[alien] = symbol
+		}
+	}
+
+	return nil
 }
 
-func (p *parser) FixTypo(param string) string {
+// validDictionary reports the first word of dictionary given a symbol out of system.
+func validDictionary(system converters.NumeralSystem, dictionary map[string]string) error {
+	for alien, symbol := range dictionary {
+		if !system.IsSymbol(strings.ToUpper(symbol)) {
+			return fmt.Errorf("%w: symbol of '%s' is '%s'", storages.ErrInvalidKnowledgeBase, alien, symbol)
+		}
+	}
 
-	paramArr := strings.Split(param, " ")
-	for i, param := range paramArr {
-		for _, reservedKeyword := range reservedKeywords {
-			if strings.Contains(param, reservedKeyword) && param != reservedKeyword {
-				if strings.HasPrefix(param, reservedKeyword) {
-					res := strings.Split(param, reservedKeyword)
-					res[0] = reservedKeyword
-					fixedParam := strings.Join(res, " ")
-					paramArr[i] = fixedParam
-				} else if strings.HasSuffix(param, reservedKeyword) {
-					res := strings.Split(param, reservedKeyword)
-					res[1] = reservedKeyword
-					fixedParam := strings.Join(res, " ")
-					paramArr[i] = fixedParam
-				}
-			}
+	return nil
+}
+
+// Reset forgets every learned alien word of every dialect, metal price of
+// every date and exchange rate along with the declared numeral system and the
+// conflicts.
+func (p *parser) Reset() {
+	p.numeralSystem = p.defaultNumeralSystem
+	p.dialects = map[string]map[string]string{}
+	p.history = map[string]map[string]rationals.Rational{}
+	p.rates = map[string]map[string]rationals.Rational{}
+	p.wordSources = map[string]Source{}
+	p.priceSources = map[string]Source{}
+	p.rateSources = map[string]Source{}
+	p.conflicts = nil
+
+	for alien := range p.alienDictionary {
+		delete(p.alienDictionary, alien)
+	}
+
+	for metal := range p.metalValue {
+		delete(p.metalValue, metal)
+	}
+}
+
+// ParseCurrency learns the alien word written as "<alien word> is <symbol>",
+// where symbol belongs to the numeral system, in the dialect declared by "on
+// <dialect>" or the default one, the numeral system declared by
+// "numerals are <system>" before any word of any dialect or the exchange rate
+// declared by "<amount> <currency> is <amount> <currency>". A word given
+// another symbol, or given the symbol of another word, and a rate given
+// another value are resolved by the conflict policy. Nothing is learned once
+// ctx is done.
+func (p *parser) ParseCurrency(ctx context.Context, line Line) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	statement, err := Parse(line.Text)
+	if err != nil {
+		return false, nil
+	}
+
+	if declaration, ok := statement.(*NumeralsDeclaration); ok {
+		if declaration.Dialect.Text != "" {
+			return false, locate(line, positioned(declaration.Dialect, fmt.Errorf("%w '%s'", ErrScopedNumerals, declaration.Dialect.Text)))
+		}
+
+		system, err := p.numerals.Get(declaration.System.Text)
+		if err != nil {
+			return false, locate(line, positioned(declaration.System, err))
+		}
+
+		if system.Name() != p.numeralSystem.Name() && len(p.dialectWords()) > 0 {
+			return false, locate(line, positioned(declaration.System, fmt.Errorf("%w '%s'", ErrNumeralsRedeclared, declaration.System.Text)))
 		}
+
+		p.numeralSystem = system
+		return true, nil
 	}
 
-	result := strings.Join(paramArr, " ")
+	if definition, ok := statement.(*RateDefinition); ok {
+		return p.learnRate(line, definition)
+	}
 
-	return result
+	definition, ok := statement.(*CurrencyDefinition)
+	if !ok || !p.isSymbol(definition.Roman.Text) {
+		return false, nil
+	}
+
+	dialect := definition.Dialect.Text
+	dictionary := p.definedDictionary(definition.Dialect)
+	word := definition.Word.Text
+	symbol := definition.Roman.Text
+	source := Source{File: line.File, Line: line.Number, Column: definition.Word.Column, Text: line.Text}
+
+	if previous, ok := dictionary[word]; ok && previous != symbol {
+		learned, err := p.resolve(Conflict{
+			Kind:          ConflictRedefinedWord,
+			Name:          qualified(dialect, word),
+			Value:         symbol,
+			PreviousValue: previous,
+			Previous:      p.wordSources[qualified(dialect, word)],
+			Current:       source,
+		})
+		if !learned {
+			return err == nil, err
+		}
+	}
+
+	if other, ok := wordOf(dictionary, symbol, word); ok {
+		learned, err := p.resolve(Conflict{
+			Kind:     ConflictSharedSymbol,
+			Name:     qualified(dialect, word),
+			Value:    symbol,
+			Other:    qualified(dialect, other),
+			Previous: p.wordSources[qualified(dialect, other)],
+			Current:  source,
+		})
+		if !learned {
+			return err == nil, err
+		}
+	}
+
+	dictionary[word] = symbol
+	p.wordSources[qualified(dialect, word)] = source
+
+	return true, nil
+}
+
+// wordOf returns the first word of dictionary other than word standing for symbol.
+func wordOf(dictionary map[string]string, symbol string, word string) (string, bool) {
+	words := keys(dictionary)
+	slices.Sort(words)
+
+	for _, other := range words {
+		if other != word && strings.EqualFold(dictionary[other], symbol) {
+			return other, true
+		}
+	}
+
+	return "", false
 }
 
+// sayValue writes value with the alien words of dictionary, value must be a
+// whole number in the range of the numeral system.
+func (p *parser) sayValue(value rationals.Rational, dictionary map[string]string) ([]string, error) {
+	low, high := p.numeralSystem.Range()
+	if !value.IsInt() || value.Cmp(rationals.FromInt(low)) < 0 || value.Cmp(rationals.FromInt(high)) > 0 {
+		return nil, fmt.Errorf("%w '%s'", converters.ErrOutOfRange, value)
+	}
+
+	// the range check keeps the number within an int
+	number, _ := value.Floor()
+
+	return p.sayNumber(number, dictionary)
+}
+
+// ArabicToAlien writes number in the numeral system of the dictionary with its alien words.
+func (p *parser) ArabicToAlien(number int) ([]string, error) {
+	return p.sayNumber(number, p.alienDictionary)
+}
+
+// sayNumber writes number in the numeral system with the alien words of dictionary.
+func (p *parser) sayNumber(number int, dictionary map[string]string) ([]string, error) {
+	symbols, err := p.numeralSystem.Format(number)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.converter.RomanToAlien(dictionary, symbols)
+}
+
+// GetCurrencyValue converts the alien words of the default dialect.
 func (p *parser) GetCurrencyValue(param []string) (int, error) {
+	return p.valueOf(param, p.alienDictionary)
+}
 
-	result, err := p.converter.AlienToRoman(p.alienDictionary, param)
+// valueOf converts the alien words of dictionary.
+func (p *parser) valueOf(param []string, dictionary map[string]string) (int, error) {
+	result, err := p.converter.AlienToRoman(dictionary, param)
 	if err != nil {
 		return 0, err
 	}
 
-	resultValue, err := p.converter.RomanToArabic(result)
+	resultValue, err := p.numeralSystem.Parse(result)
 	if err != nil {
 		return 0, err
 	}
 	return resultValue, nil
 }
 
-// currently only support gold, silver, iron
-func (p *parser) ParseMetal(param []string) (bool, error) {
-	isIdx := slices.Index(param, "is")
-	creditsIdx := slices.Index(param, "credits")
-	found := false
+// ParseMetal learns the price of any commodity written as "<alien number> <commodity> is <N> credits",
+// or <N> of any currency with an exchange rate to credits, effective from the
+// date declared by "on <date>" or without date. A commodity given another
+// price at the same date is resolved by the conflict policy. Nothing is
+// learned once ctx is done.
+func (p *parser) ParseMetal(ctx context.Context, line Line) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
 
-	if isIdx != -1 && creditsIdx == len(param)-1 {
-		// check if previous value is metal
-		if slices.Index(metalSymbols, param[isIdx-1]) != -1 {
-			strTotalValue := param[isIdx+1]
+	statement, err := Parse(line.Text)
+	if err != nil {
+		return false, nil
+	}
 
-			totalValue, err := strconv.Atoi(strTotalValue)
-			if err != nil {
-				return false, err
-			}
+	definition, ok := statement.(*CommodityDefinition)
+	if !ok || !p.isCommodity(definition.Quantity.Commodity.Text) {
+		return false, nil
+	}
 
-			alienValue := slices.Clone(param[:isIdx-1])
-			romanizedAlienValue, err := p.converter.AlienToRoman(p.alienDictionary, alienValue)
-			if err != nil {
-				return false, err
-			}
+	if !p.isAllowedCommodity(definition.Quantity.Commodity.Text) {
+		commodity := definition.Quantity.Commodity
+		return false, locate(line, positioned(commodity, fmt.Errorf("commodity '%s' is %w", commodity.Text, ErrCommodityNotAllowed)))
+	}
 
-			romanValue, err := p.converter.RomanToArabic(romanizedAlienValue)
-			if err != nil {
-				return false, err
-			}
+	// credits are written as decimal numbers only, as the amounts of the rates
+	if definition.Credits.Kind != TokenNumber {
+		return false, locate(line, positioned(definition.Credits, fmt.Errorf("%w '%s'", rationals.ErrInvalidRational, definition.Credits.Text)))
+	}
+
+	totalValue, err := rationals.Parse(definition.Credits.Text)
+	if err != nil {
+		return false, locate(line, positioned(definition.Credits, err))
+	}
 
-			metalValue := float64(totalValue) / float64(romanValue)
+	// nothing is sold for no credits at all
+	if totalValue.Sign() <= 0 {
+		return false, locate(line, positioned(definition.Credits, fmt.Errorf("%w '%s'", converters.ErrOutOfRange, totalValue)))
+	}
+
+	// prices are kept in credits whatever the currency they are given in
+	totalValue, err = p.exchange(totalValue, definition.Currency.Text, baseCurrency)
+	if err != nil {
+		return false, locate(line, positioned(definition.Currency, err))
+	}
+
+	romanValue, err := p.numberValue(definition.Quantity.Number)
+	if err != nil {
+		return false, locate(line, err)
+	}
 
-			p.metalValue[param[isIdx-1]] = metalValue
-			found = true
+	// a price is per unit, nothing can be learned from no unit at all
+	if romanValue == 0 {
+		return false, locate(line, positioned(definition.Quantity.Number.Words[0], fmt.Errorf("%w '%d'", converters.ErrOutOfRange, romanValue)))
+	}
+
+	metalValue := totalValue.Quo(rationals.FromInt(romanValue))
+
+	commodity := definition.Quantity.Commodity
+	date := definition.Date.Text
+	source := Source{File: line.File, Line: line.Number, Column: commodity.Column, Text: line.Text}
+
+	previous, ok := p.metalValue[commodity.Text]
+	if date != "" {
+		previous, ok = p.history[commodity.Text][date]
+	}
+
+	if ok && !previous.Equal(metalValue) {
+		learned, err := p.resolve(Conflict{
+			Kind:          ConflictRedefinedPrice,
+			Name:          commodity.Text,
+			Value:         p.rounding.Format(metalValue),
+			PreviousValue: p.rounding.Format(previous),
+			Previous:      p.priceSources[priceKey(commodity.Text, date)],
+			Current:       source,
+		})
+		if !learned {
+			return err == nil, err
 		}
 	}
 
-	return found, nil
+	if date != "" {
+		p.addPrice(commodity.Text, date, metalValue)
+	} else {
+		p.metalValue[commodity.Text] = metalValue
+	}
+	p.priceSources[priceKey(commodity.Text, date)] = source
+
+	return true, nil
+}
+
+// isCommodity reports whether word can name a commodity, reserved keywords,
+// roman symbols, alien words of any dialect and numbers never do.
+func (p *parser) isCommodity(word string) bool {
+	if word == "" || slices.Contains(reservedKeywords, word) || p.isSymbol(word) {
+		return false
+	}
+
+	if p.isAlienWord(word) {
+		return false
+	}
+
+	_, err := strconv.Atoi(word)
+	return err != nil
+}
+
+// isAllowedCommodity reports whether commodity can be learned, it is neither
+// deny-listed nor missing from a non-empty allow-list.
+func (p *parser) isAllowedCommodity(commodity string) bool {
+	if slices.Contains(p.commodityDenyList, commodity) {
+		return false
+	}
+
+	return len(p.commodityAllowList) == 0 || slices.Contains(p.commodityAllowList, commodity)
 }
 
-func (p *parser) ProcessQuestion(questions []string) ([]string, error) {
-	answers := []string{}
+// ProcessQuestion answers the questions in order, once ctx is done it returns
+// the answers given so far along with the error of ctx.
+func (p *parser) ProcessQuestion(ctx context.Context, questions []Line) ([]Answer, error) {
+	answers := []Answer{}
 	for _, question := range questions {
-		questionArr := strings.Split(question, " ")
-
-		switch questionArr[0] {
-		case "how":
-			if questionArr[1] == "much" {
-				answer, err := p.HowMuchQuestion(questionArr)
-				if err != nil {
-					answer = "I have no idea what you are talking about"
-				}
-				answers = append(answers, answer)
-			} else if questionArr[1] == "many" {
-				answer, err := p.HowManyQuestion(questionArr)
-				if err != nil {
-					answer = "I have no idea what you are talking about"
-				}
-				answers = append(answers, answer)
-			}
-		case "does":
-			answer, err := p.DoesQuestion(questionArr)
-			if err != nil {
-				answer = "I have no idea what you are talking about"
-			}
-			answers = append(answers, answer)
-		case "is":
-			answer, err := p.IsQuestion(questionArr)
-			if err != nil {
-				answer = "I have no idea what you are talking about"
-			}
-			answers = append(answers, answer)
-		default:
-			answer := "I have no idea what you are talking about"
-			answers = append(answers, answer)
+		if err := ctx.Err(); err != nil {
+			return answers, err
+		}
+
+		answer, err := p.answer(question.Text)
+		err = locate(question, err)
+
+		answer.File = question.File
+		answer.Line = question.Number
+		answer.Question = question.Raw
+		// a line built without its raw text is asked as normalized
+		if answer.Question == "" {
+			answer.Question = question.Text
 		}
+		answer.Err = err
+		answer.Category = Categorize(err)
+
+		answers = append(answers, answer)
 	}
 	return answers, nil
 }
 
-func (p *parser) HowMuchQuestion(question []string) (string, error) {
-	isIdx := slices.Index(question, "is")
-	questionMarkIdx := slices.Index(question, "?")
+func (p *parser) answer(question string) (Answer, error) {
+	statement, err := Parse(question)
+	if err != nil {
+		return Answer{Kind: questionKind(Lex(question))}, err
+	}
+
+	switch statement := statement.(type) {
+	case *HowMuchQuestion:
+		return p.HowMuchQuestion(statement)
+	case *ArithmeticQuestion:
+		return p.ArithmeticQuestion(statement)
+	case *HowManyQuestion:
+		return p.HowManyQuestion(statement)
+	case *DoesQuestion:
+		return p.DoesQuestion(statement)
+	case *IsQuestion:
+		return p.IsQuestion(statement)
+	case *SayQuestion:
+		return p.SayQuestion(statement)
+	case *HowManyForQuestion:
+		return p.HowManyForQuestion(statement)
+	case *ExchangeQuestion:
+		// a currency is asked like credits are
+		if !p.isPriced(statement.Commodity.Text) && p.isCurrency(statement.Commodity.Text) {
+			return p.HowManyQuestion(&HowManyQuestion{Currency: statement.Commodity, Quantity: statement.Quantity, AsOf: statement.AsOf})
+		}
+		return p.ExchangeQuestion(statement)
+	case *NumeralsDeclaration:
+		_, err := p.numerals.Get(statement.System.Text)
+		return Answer{Kind: QuestionKindUnknown}, positioned(statement.System, err)
+	default:
+		return Answer{Kind: QuestionKindUnknown}, ErrMalformedQuestion
+	}
+}
 
-	alienValue := question[isIdx+1 : questionMarkIdx]
+func (p *parser) HowMuchQuestion(question *HowMuchQuestion) (Answer, error) {
+	answer := Answer{Kind: QuestionKindHowMuch}
 
-	currencyValue, err := p.GetCurrencyValue(alienValue)
+	operand, err := p.numberOperand(question.Number)
 	if err != nil {
-		return "", err
+		return answer, err
 	}
 
-	answer := strings.Join(alienValue, " ") + " is " + strconv.Itoa(currencyValue)
+	answer.Operands = []Operand{operand}
+	answer.Value = rationals.FromInt(operand.Value)
+
 	return answer, nil
 }
 
-func (p *parser) HowManyQuestion(question []string) (string, error) {
-	isIdx := slices.Index(question, "is")
-	questionMarkIdx := slices.Index(question, "?")
+// HowManyQuestion answers with the price of the quantity in the currency asked,
+// converted from credits through the exchange rates.
+func (p *parser) HowManyQuestion(question *HowManyQuestion) (Answer, error) {
+	answer := Answer{Kind: QuestionKindHowMany, AsOf: question.AsOf.Text}
+
+	operand, err := p.quantityOperand(question.Quantity, question.AsOf)
+	if err != nil {
+		return answer, err
+	}
+
+	value := operand.Credits
+	if !question.Currency.Is(baseCurrency) {
+		value, err = p.exchange(value, baseCurrency, question.Currency.Text)
+		if err != nil {
+			return answer, positioned(question.Currency, err)
+		}
+
+		answer.Currency = question.Currency.Text
+	}
+
+	answer.Operands = []Operand{operand}
+	answer.Value = value
+	answer.Commodity = operand.Commodity
+
+	return answer, nil
+}
 
-	alienValue := question[isIdx+1 : questionMarkIdx-1]
-	metal := question[questionMarkIdx-1]
+func (p *parser) DoesQuestion(question *DoesQuestion) (Answer, error) {
+	answer := Answer{Kind: QuestionKindDoes, AsOf: question.AsOf.Text}
 
-	currencyValue, err := p.GetCurrencyValue(alienValue)
+	operand1, err := p.quantityOperand(question.Left, question.AsOf)
 	if err != nil {
-		return "", err
+		return answer, err
 	}
 
-	metalValue := p.metalValue[metal]
+	operand2, err := p.quantityOperand(question.Right, question.AsOf)
+	if err != nil {
+		return answer, err
+	}
 
-	totalValue := float64(currencyValue) * metalValue
+	answer.Operands = []Operand{operand1, operand2}
+	answer.Comparison = compare(operand1.Credits, operand2.Credits)
 
-	var strTotalValue string
-	strTotalValue = fmt.Sprintf("%.1f", totalValue)
+	return answer, nil
+}
 
-	if float64(int64(totalValue)) == totalValue {
-		strTotalValue = fmt.Sprintf("%.0f", totalValue)
+func (p *parser) IsQuestion(question *IsQuestion) (Answer, error) {
+	answer := Answer{Kind: QuestionKindIs}
+
+	operand1, err := p.numberOperand(question.Left)
+	if err != nil {
+		return answer, err
+	}
+
+	operand2, err := p.numberOperand(question.Right)
+	if err != nil {
+		return answer, err
 	}
 
-	answer := strings.Join(alienValue, " ") + " " + metal + " is " + strTotalValue + " Credits"
+	answer.Operands = []Operand{operand1, operand2}
+	answer.Comparison = compare(rationals.FromInt(operand1.Value), rationals.FromInt(operand2.Value))
 
 	return answer, nil
 }
 
-func (p *parser) DoesQuestion(question []string) (string, error) {
-	doesIdx := slices.Index(question, "does")
-	hasIdx := slices.Index(question, "has")
-	questionMarkIdx := slices.Index(question, "?")
-	thanIdx := slices.Index(question, "than")
-	answer := ""
+func (p *parser) SayQuestion(question *SayQuestion) (Answer, error) {
+	answer := Answer{Kind: QuestionKindSay}
 
-	value1Arr := question[doesIdx+1 : hasIdx]
-	value2Arr := question[thanIdx+1 : questionMarkIdx]
+	number, err := strconv.Atoi(question.Number.Text)
+	if err != nil {
+		return answer, positioned(question.Number, fmt.Errorf("%w '%s'", converters.ErrInvalidNumber, question.Number.Text))
+	}
 
-	metal1 := value1Arr[len(value1Arr)-1]
-	metal2 := value2Arr[len(value2Arr)-1]
+	dictionary, err := p.dictionaryOf(question.Dialect)
+	if err != nil {
+		return answer, err
+	}
+
+	alien, err := p.sayNumber(number, dictionary)
+	if err != nil {
+		return answer, positioned(question.Number, err)
+	}
 
-	metal1Value := p.metalValue[metal1]
-	metal2Value := p.metalValue[metal2]
+	answer.Operands = []Operand{{Alien: alien, Value: number, Dialect: dialectKey(question.Dialect)}}
+	answer.Value = rationals.FromInt(number)
+	answer.Alien = alien
+
+	return answer, nil
+}
 
-	value1Arr = value1Arr[:len(value1Arr)-1]
-	value2Arr = value2Arr[:len(value2Arr)-1]
+// HowManyForQuestion answers with the whole amount of commodity the credits buy.
+func (p *parser) HowManyForQuestion(question *HowManyForQuestion) (Answer, error) {
+	answer := Answer{Kind: QuestionKindHowManyFor, AsOf: question.AsOf.Text}
 
-	value1, err := p.GetCurrencyValue(value1Arr)
+	credits, err := rationals.Parse(question.Credits.Text)
 	if err != nil {
-		return "", err
+		return answer, positioned(question.Credits, err)
 	}
 
-	value2, err := p.GetCurrencyValue(value2Arr)
+	commodity := question.Commodity.Text
+	metalValue, err := p.commodityValue(question.Commodity, question.AsOf)
 	if err != nil {
-		return "", err
+		return answer, err
 	}
 
-	totalValue1 := math.Trunc(float64(value1) * float64(metal1Value))
-	totalValue2 := math.Trunc(float64(value2) * float64(metal2Value))
+	// a free commodity would buy an endless amount
+	if metalValue.Sign() == 0 {
+		return answer, positioned(question.Commodity, fmt.Errorf("%w '%s'", converters.ErrOutOfRange, commodity))
+	}
 
-	if totalValue1 < totalValue2 {
-		answer = strings.Join(value1Arr, " ") + " " + cases.Title(language.AmericanEnglish, cases.Compact).String(metal1) + " has less Credits than " + strings.Join(value2Arr, " ") + " " + cases.Title(language.AmericanEnglish, cases.Compact).String(metal2)
-	} else if totalValue1 > totalValue2 {
-		answer = strings.Join(value1Arr, " ") + " " + cases.Title(language.AmericanEnglish, cases.Compact).String(metal1) + " has more Credits than " + strings.Join(value2Arr, " ") + " " + cases.Title(language.AmericanEnglish, cases.Compact).String(metal2)
-	} else {
-		answer = strings.Join(value1Arr, " ") + " " + cases.Title(language.AmericanEnglish, cases.Compact).String(metal1) + " has equal Credits to " + strings.Join(value2Arr, " ") + " " + cases.Title(language.AmericanEnglish, cases.Compact).String(metal2)
+	dictionary, err := p.dictionaryOf(question.Dialect)
+	if err != nil {
+		return answer, err
+	}
+
+	quotient := credits.Quo(metalValue)
+	units, ok := quotient.Floor()
+	low, high := p.numeralSystem.Range()
+	if !ok || units < low || units > high {
+		return answer, positioned(question.Credits, fmt.Errorf("%w '%s'", converters.ErrOutOfRange, quotient.Round(0, rationals.RoundDown)))
+	}
+
+	alien, err := p.sayNumber(units, dictionary)
+	if err != nil {
+		return answer, positioned(question.Credits, err)
 	}
 
+	answer.Operands = []Operand{{Alien: alien, Value: units, Dialect: dialectKey(question.Dialect), Commodity: commodity, Credits: rationals.FromInt(units).Mul(metalValue)}}
+	answer.Value = credits
+	answer.Commodity = commodity
+	answer.Alien = alien
+
 	return answer, nil
 }
 
-func (p *parser) IsQuestion(question []string) (string, error) {
-	isIdx := slices.Index(question, "is")
-	largerIdx := slices.Index(question, "larger")
-	smallerIdx := slices.Index(question, "smaller")
-	questionMarkIdx := slices.Index(question, "?")
-	thanIdx := slices.Index(question, "than")
-	answer := ""
+// ExchangeQuestion answers with the exact amount of commodity worth as many
+// credits as the quantity of another commodity. The amount is also said in
+// alien words when it is a whole number the dictionary can say.
+func (p *parser) ExchangeQuestion(question *ExchangeQuestion) (Answer, error) {
+	answer := Answer{Kind: QuestionKindExchange, AsOf: question.AsOf.Text}
 
-	var value1Arr []string
-	value2Arr := question[thanIdx+1 : questionMarkIdx]
+	operand, err := p.quantityOperand(question.Quantity, question.AsOf)
+	if err != nil {
+		return answer, err
+	}
 
-	if largerIdx != -1 {
-		value1Arr = question[isIdx+1 : largerIdx]
-	} else if smallerIdx != -1 {
-		value1Arr = question[isIdx+1 : smallerIdx]
-	} else {
-		return "", nil
+	commodity := question.Commodity.Text
+	metalValue, err := p.commodityValue(question.Commodity, question.AsOf)
+	if err != nil {
+		return answer, err
+	}
+
+	// a free commodity would be exchanged for an endless amount
+	if metalValue.Sign() == 0 {
+		return answer, positioned(question.Commodity, fmt.Errorf("%w '%s'", converters.ErrOutOfRange, commodity))
 	}
 
-	value1, err := p.GetCurrencyValue(value1Arr)
+	amount := operand.Credits.Quo(metalValue)
+
+	answer.Operands = []Operand{operand}
+	answer.Value = amount
+	answer.Commodity = commodity
+
+	// the amount is said in the dialect of the quantity, known since it was converted
+	dictionary, _ := p.dictionary(operand.Dialect)
+	if alien, err := p.sayValue(amount, dictionary); err == nil {
+		answer.Alien = alien
+	}
+
+	return answer, nil
+}
+
+func (p *parser) numberOperand(number AlienNumber) (Operand, error) {
+	value, err := p.numberValue(number)
 	if err != nil {
-		return "", err
+		return Operand{}, err
 	}
-	value2, err := p.GetCurrencyValue(value2Arr)
+
+	return Operand{Alien: number.Strings(), Value: value, Dialect: dialectKey(number.Dialect)}, nil
+}
+
+// numberValue converts number with the words of its dialect, reporting an
+// unknown word at its own column along with the closest known word, or the
+// dialect it belongs to.
+func (p *parser) numberValue(number AlienNumber) (int, error) {
+	dictionary, err := p.dictionaryOf(number.Dialect)
 	if err != nil {
-		return "", err
+		return 0, err
 	}
 
-	if value1 > value2 {
-		answer = strings.Join(value1Arr, " ") + " is larger than " + strings.Join(value2Arr, " ")
-	} else if value1 < value2 {
-		answer = strings.Join(value1Arr, " ") + " is smaller than " + strings.Join(value2Arr, " ")
-	} else {
-		answer = strings.Join(value1Arr, " ") + " is equal to " + strings.Join(value2Arr, " ")
+	value, err := p.valueOf(number.Strings(), dictionary)
+	if err == nil {
+		return value, nil
 	}
 
-	return answer, nil
+	if errors.Is(err, converters.ErrUnknownAlienWord) {
+		for _, word := range number.Words {
+			if _, ok := dictionary[word.Text]; ok {
+				continue
+			}
+
+			if other, ok := p.dialectOf(word.Text, dialectKey(number.Dialect)); ok {
+				return 0, positioned(word, fmt.Errorf("%w, '%s' is a word of %s, not of %s", ErrMixedDialects, word.Text, dialectName(other), dialectName(number.Dialect.Text)))
+			}
+
+			diagnostic := positioned(word, err)
+			diagnostic.Suggestion = suggest(word.Text, keys(dictionary))
+			return 0, diagnostic
+		}
+	}
+
+	return 0, positioned(number.Words[0], err)
+}
+
+// quantityOperand prices quantity as of date, at the current price when date is the zero token.
+func (p *parser) quantityOperand(quantity Quantity, date Token) (Operand, error) {
+	operand, err := p.numberOperand(quantity.Number)
+	if err != nil {
+		return Operand{}, err
+	}
+
+	metalValue, err := p.commodityValue(quantity.Commodity, date)
+	if err != nil {
+		return Operand{}, err
+	}
+
+	operand.Commodity = quantity.Commodity.Text
+	operand.Credits = rationals.FromInt(operand.Value).Mul(metalValue)
+
+	return operand, nil
+}
+
+// commodityValue returns the price of a unit of commodity effective at date,
+// the current price when date is the zero token.
+func (p *parser) commodityValue(commodity Token, date Token) (rationals.Rational, error) {
+	metalValue, ok := p.priceOf(commodity.Text, date.Text)
+	if ok {
+		return metalValue, nil
+	}
+
+	// the commodity was only priced later on
+	if p.isPriced(commodity.Text) {
+		return rationals.Rational{}, positioned(date, fmt.Errorf("%w of '%s' as of %s", ErrNoPrice, commodity.Text, date.Text))
+	}
+
+	diagnostic := positioned(commodity, fmt.Errorf("%w '%s'", ErrUnknownCommodity, commodity.Text))
+	diagnostic.Suggestion = suggest(commodity.Text, p.pricedCommodities())
+	return rationals.Rational{}, diagnostic
+}
+
+// isSymbol reports whether word is a symbol of the numeral system, ignoring case.
+func (p *parser) isSymbol(word string) bool {
+	return p.numeralSystem.IsSymbol(strings.ToUpper(word))
+}
+
+func lowerAll(words []string) []string {
+	result := make([]string, 0, len(words))
+	for _, word := range words {
+		result = append(result, strings.ToLower(word))
+	}
+
+	return result
+}
+
+func keys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+
+	return result
 }
@@ -1,11 +1,15 @@
 package parsers_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
 	mockConverter "github.com/arieffian/roman-alien-currency/internal/pkg/converters/mocks"
 	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
 	"github.com/go-test/deep"
 	"github.com/golang/mock/gomock"
 )
@@ -17,19 +21,15 @@ func TestParseCurrency(t *testing.T) {
 	parser := parsers.NewParser(parsers.NewParserParams{
 		Converter:       converter,
 		AlienDictionary: map[string]string{},
-		MetalValue:      map[string]float64{},
+		MetalValue:      map[string]rationals.Rational{},
 	})
 
-	acceptedParams := []string{
-		"glob", "is", "i",
-	}
+	acceptedParams := "glob is i"
 
-	notAcceptedParams := []string{
-		"glob", "prok", "gold", "is", "57800", "credits",
-	}
+	notAcceptedParams := "glob prok gold is 57800 credits"
 
 	type args struct {
-		param []string
+		param string
 	}
 
 	type want struct {
@@ -52,6 +52,26 @@ func TestParseCurrency(t *testing.T) {
 				result: true,
 			},
 		},
+		{
+			name: "when symbol is a vinculum should return success",
+			args: args{
+				param: "pish is v̅",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: true,
+			},
+		},
+		{
+			name: "when symbol is an apostrophus should return success",
+			args: args{
+				param: "tegj is ciↄ",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: true,
+			},
+		},
 		{
 			name: "when input is invalid should return success",
 			args: args{
@@ -69,7 +89,10 @@ func TestParseCurrency(t *testing.T) {
 
 			tc.beforeEach(t, &tc.args)
 
-			result := parser.ParseCurrency(tc.args.param)
+			result, err := parser.ParseCurrency(context.Background(), parsers.Line{Number: 1, Text: tc.args.param})
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
 
 			if diff := deep.Equal(result, tc.want.result); diff != nil {
 				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
@@ -86,7 +109,7 @@ func TestGetCurrencyValue(t *testing.T) {
 	parser := parsers.NewParser(parsers.NewParserParams{
 		Converter:       converter,
 		AlienDictionary: map[string]string{},
-		MetalValue:      map[string]float64{},
+		MetalValue:      map[string]rationals.Rational{},
 	})
 
 	validParam := []string{
@@ -207,27 +230,19 @@ func TestParserMetal(t *testing.T) {
 		AlienDictionary: map[string]string{
 			"glob": "i",
 		},
-		MetalValue: map[string]float64{},
+		MetalValue: map[string]rationals.Rational{},
 	})
 
-	validParam := []string{
-		"glob", "gold", "is", "57800", "credits",
-	}
+	validParam := "glob gold is 57800 credits"
 
-	invalidAlienCurrencyParam := []string{
-		"glob", "prok", "gold", "is", "57800", "credits",
-	}
+	invalidAlienCurrencyParam := "glob prok gold is 57800 credits"
 
-	invalidCreditsParam := []string{
-		"glob", "gold", "is", "57800a", "credits",
-	}
+	invalidCreditsParam := "glob gold is 57800a credits"
 
-	invalidRomanParam := []string{
-		"glob", "glob", "glob", "glob", "gold", "is", "57800", "credits",
-	}
+	invalidRomanParam := "glob glob glob glob gold is 57800 credits"
 
 	type args struct {
-		param []string
+		param string
 	}
 
 	type want struct {
@@ -282,7 +297,7 @@ func TestParserMetal(t *testing.T) {
 			},
 			want: want{
 				result: false,
-				error:  errors.New("invalid roman number"),
+				error:  errors.New("1:1: invalid roman number"),
 			},
 		},
 		{
@@ -299,7 +314,7 @@ func TestParserMetal(t *testing.T) {
 			},
 			want: want{
 				result: false,
-				error:  errors.New("invalid alien number"),
+				error:  errors.New("1:1: invalid alien number"),
 			},
 		},
 		{
@@ -310,7 +325,62 @@ func TestParserMetal(t *testing.T) {
 			beforeEach: func(t *testing.T, a *args) {},
 			want: want{
 				result: false,
-				error:  errors.New(`strconv.Atoi: parsing "57800a": invalid syntax`),
+				error:  errors.New("1:14: invalid number '57800a'"),
+			},
+		},
+		{
+			name: "when credit is negative should return error",
+			args: args{
+				param: "glob gold is -5 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  errors.New("1:14: invalid number '-5'"),
+			},
+		},
+		{
+			name: "when credit is hexadecimal should return error",
+			args: args{
+				param: "glob gold is 0x10 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  errors.New("1:14: invalid number '0x10'"),
+			},
+		},
+		{
+			name: "when credit is a fraction should return error",
+			args: args{
+				param: "glob gold is 3/7 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  errors.New("1:14: invalid number '3/7'"),
+			},
+		},
+		{
+			name: "when credit is in scientific notation should return error",
+			args: args{
+				param: "glob gold is 1e999999 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  errors.New("1:14: invalid number '1e999999'"),
+			},
+		},
+		{
+			name: "when credit is zero should return error",
+			args: args{
+				param: "glob gold is 0 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  errors.New("1:14: number out of range '0'"),
 			},
 		},
 	}
@@ -320,7 +390,7 @@ func TestParserMetal(t *testing.T) {
 
 			tc.beforeEach(t, &tc.args)
 
-			result, err := parser.ParseMetal(tc.args.param)
+			result, err := parser.ParseMetal(context.Background(), parsers.Line{Number: 1, Text: tc.args.param})
 
 			if err != nil || tc.want.error != nil {
 				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
@@ -345,41 +415,49 @@ func TestProcessQuestion(t *testing.T) {
 		AlienDictionary: map[string]string{
 			"glob": "I",
 		},
-		MetalValue: map[string]float64{
-			"Gold": 100,
+		MetalValue: map[string]rationals.Rational{
+			"Gold": rationals.FromInt(100),
 		},
 	})
 
-	validHowMuchParam := []string{
-		"how much is glob glob ?",
+	validHowMuchParam := []parsers.Line{
+		{Number: 1, Text: "how much is glob glob ?"},
+	}
+
+	invalidHowMuchParam := []parsers.Line{
+		{Number: 1, Text: "how much is glob glob prok ?"},
+	}
+
+	validHowManyParam := []parsers.Line{
+		{Number: 1, Text: "how many Credits is glob glob Gold ?"},
 	}
 
-	invalidHowMuchParam := []string{
-		"how much is glob glob prok ?",
+	invalidHowManyParam := []parsers.Line{
+		{Number: 1, Text: "how many Credits is glob prok Gold ?"},
 	}
 
-	validHowManyParam := []string{
-		"how many Credits is glob glob Gold ?",
+	validDoesParam := []parsers.Line{
+		{Number: 1, Text: "does glob glob Gold has more Credits than glob Gold ?"},
 	}
 
-	invalidHowManyParam := []string{
-		"how many Credits is glob prok Gold ?",
+	validIsParam := []parsers.Line{
+		{Number: 1, Text: "is glob larger than glob glob ?"},
 	}
 
-	validDoesParam := []string{
-		"does glob glob Gold has more Credits than glob Gold ?",
+	unknownCommodityParam := []parsers.Line{
+		{Number: 1, Text: "how many Credits is glob glob Dirt ?"},
 	}
 
-	validIsParam := []string{
-		"is glob larger than glob glob ?",
+	misspelledCommodityParam := []parsers.Line{
+		{File: "input", Number: 7, Text: "how many Credits is glob glob Gld ?", Raw: "  How Many Credits is glob glob Gld ?"},
 	}
 
 	type args struct {
-		param []string
+		param []parsers.Line
 	}
 
 	type want struct {
-		result []string
+		result []parsers.Answer
 		error  error
 	}
 
@@ -406,8 +484,16 @@ func TestProcessQuestion(t *testing.T) {
 					Return(2, nil)
 			},
 			want: want{
-				result: []string{"glob glob is 2"},
-				error:  nil,
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob glob ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Operands: []parsers.Operand{{Alien: []string{"glob", "glob"}, Value: 2}},
+						Value:    rationals.FromInt(2),
+					},
+				},
+				error: nil,
 			},
 		},
 		{
@@ -419,11 +505,19 @@ func TestProcessQuestion(t *testing.T) {
 				converter.
 					EXPECT().
 					AlienToRoman(gomock.Any(), gomock.Any()).
-					Return("", errors.New("invalid alien number"))
+					Return("", converters.ErrUnknownAlienWord)
 			},
 			want: want{
-				result: []string{"I have no idea what you are talking about"},
-				error:  nil,
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob glob prok ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Err:      errors.New("1:23: unknown alien word"),
+						Category: parsers.ErrorCategoryUnknownAlienWord,
+					},
+				},
+				error: nil,
 			},
 		},
 		{
@@ -443,8 +537,17 @@ func TestProcessQuestion(t *testing.T) {
 					Return(2, nil)
 			},
 			want: want{
-				result: []string{"glob glob Gold is 200 Credits"},
-				error:  nil,
+				result: []parsers.Answer{
+					{
+						Line:      1,
+						Question:  "how many Credits is glob glob Gold ?",
+						Kind:      parsers.QuestionKindHowMany,
+						Operands:  []parsers.Operand{{Alien: []string{"glob", "glob"}, Value: 2, Commodity: "Gold", Credits: rationals.FromInt(200)}},
+						Value:     rationals.FromInt(200),
+						Commodity: "Gold",
+					},
+				},
+				error: nil,
 			},
 		},
 		{
@@ -456,11 +559,19 @@ func TestProcessQuestion(t *testing.T) {
 				converter.
 					EXPECT().
 					AlienToRoman(gomock.Any(), gomock.Any()).
-					Return("", errors.New("invalid alien number"))
+					Return("", converters.ErrUnknownAlienWord)
 			},
 			want: want{
-				result: []string{"I have no idea what you are talking about"},
-				error:  nil,
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how many Credits is glob prok Gold ?",
+						Kind:     parsers.QuestionKindHowMany,
+						Err:      errors.New("1:26: unknown alien word"),
+						Category: parsers.ErrorCategoryUnknownAlienWord,
+					},
+				},
+				error: nil,
 			},
 		},
 		{
@@ -490,8 +601,19 @@ func TestProcessQuestion(t *testing.T) {
 					Return(1, nil).After(firstRomanToArabic)
 			},
 			want: want{
-				result: []string{"glob glob Gold has more Credits than glob Gold"},
-				error:  nil,
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "does glob glob Gold has more Credits than glob Gold ?",
+						Kind:     parsers.QuestionKindDoes,
+						Operands: []parsers.Operand{
+							{Alien: []string{"glob", "glob"}, Value: 2, Commodity: "Gold", Credits: rationals.FromInt(200)},
+							{Alien: []string{"glob"}, Value: 1, Commodity: "Gold", Credits: rationals.FromInt(100)},
+						},
+						Comparison: parsers.ComparisonMore,
+					},
+				},
+				error: nil,
 			},
 		},
 		{
@@ -521,8 +643,78 @@ func TestProcessQuestion(t *testing.T) {
 					Return(2, nil).After(firstRomanToArabic)
 			},
 			want: want{
-				result: []string{"glob is smaller than glob glob"},
-				error:  nil,
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "is glob larger than glob glob ?",
+						Kind:     parsers.QuestionKindIs,
+						Operands: []parsers.Operand{
+							{Alien: []string{"glob"}, Value: 1},
+							{Alien: []string{"glob", "glob"}, Value: 2},
+						},
+						Comparison: parsers.ComparisonLess,
+					},
+				},
+				error: nil,
+			},
+		},
+		{
+			name: "when how many commodity is unknown should return error",
+			args: args{
+				param: unknownCommodityParam,
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				converter.
+					EXPECT().
+					AlienToRoman(gomock.Any(), gomock.Any()).
+					Return("II", nil)
+
+				converter.
+					EXPECT().
+					RomanToArabic("II").
+					Return(2, nil)
+			},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how many Credits is glob glob Dirt ?",
+						Kind:     parsers.QuestionKindHowMany,
+						Err:      errors.New("1:31: unknown commodity 'Dirt'"),
+						Category: parsers.ErrorCategoryUnknownCommodity,
+					},
+				},
+				error: nil,
+			},
+		},
+		{
+			name: "when commodity is misspelled should suggest the closest commodity",
+			args: args{
+				param: misspelledCommodityParam,
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				converter.
+					EXPECT().
+					AlienToRoman(gomock.Any(), gomock.Any()).
+					Return("II", nil)
+
+				converter.
+					EXPECT().
+					RomanToArabic("II").
+					Return(2, nil)
+			},
+			want: want{
+				result: []parsers.Answer{
+					{
+						File:     "input",
+						Line:     7,
+						Question: "  How Many Credits is glob glob Gld ?",
+						Kind:     parsers.QuestionKindHowMany,
+						Err:      errors.New("input:7:31: unknown commodity 'Gld', did you mean 'Gold'?"),
+						Category: parsers.ErrorCategoryUnknownCommodity,
+					},
+				},
+				error: nil,
 			},
 		},
 	}
@@ -532,7 +724,7 @@ func TestProcessQuestion(t *testing.T) {
 
 			tc.beforeEach(t, &tc.args)
 
-			result, err := parser.ProcessQuestion(tc.args.param)
+			result, err := parser.ProcessQuestion(context.Background(), tc.args.param)
 
 			if err != nil || tc.want.error != nil {
 				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
@@ -553,23 +745,36 @@ func TestFixTypo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	converter := mockConverter.NewMockConverterService(ctrl)
 	parser := parsers.NewParser(parsers.NewParserParams{
-		Converter:       converter,
-		AlienDictionary: map[string]string{},
-		MetalValue:      map[string]float64{},
+		Converter: converter,
+		AlienDictionary: map[string]string{
+			"glob": "I",
+			"blob": "X",
+			"prok": "V",
+			"tegj": "L",
+		},
+		MetalValue: map[string]rationals.Rational{
+			"Gold": rationals.FromInt(100),
+		},
 	})
 
-	acceptedParams := "istegj glob glob smaller than glob prok?"
-
-	// notAcceptedParams := []string{
-	// 	"glob", "prok", "gold", "is", "57800", "credits",
-	// }
+	strictParser := parsers.NewParser(parsers.NewParserParams{
+		Converter: converter,
+		AlienDictionary: map[string]string{
+			"glob": "I",
+			"tegj": "L",
+		},
+		MetalValue:  map[string]rationals.Rational{},
+		StrictTypos: true,
+	})
 
 	type args struct {
-		param string
+		parser parsers.ParserService
+		param  parsers.Line
 	}
 
 	type want struct {
-		result string
+		result      parsers.Line
+		corrections []parsers.Correction
 	}
 
 	testcases := []struct {
@@ -579,25 +784,142 @@ func TestFixTypo(t *testing.T) {
 		want       want
 	}{
 		{
-			name: "when input is valid should return success",
+			name: "when keyword is glued to a known word should split them",
 			args: args{
-				param: acceptedParams,
+				parser: parser,
+				param:  parsers.Line{File: "input", Number: 3, Text: "Istegj glob glob smaller than glob prok?"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{File: "input", Number: 3, Text: "Is tegj glob glob smaller than glob prok?"},
+				corrections: []parsers.Correction{
+					{File: "input", Line: 3, Column: 1, From: "Istegj", To: "Is tegj", Confidence: 1},
+				},
+			},
+		},
+		{
+			name: "when words are misspelled should replace them by the closest keyword and alien word",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "how mcuh is glob prk ?"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "how much is glob prok ?"},
+				corrections: []parsers.Correction{
+					{Line: 1, Column: 5, From: "mcuh", To: "much", Confidence: 0.75},
+					{Line: 1, Column: 18, From: "prk", To: "prok", Confidence: 0.75},
+				},
+			},
+		},
+		{
+			name: "when word only contains a keyword should keep it",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "what is this ?"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "what is this ?"},
+			},
+		},
+		{
+			name: "when word is defined should keep it",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "prek is X"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "prek is X"},
+			},
+		},
+		{
+			name: "when commodity is defined should keep it",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "glob glb Glib is 10 Credits"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "glob glob Glib is 10 Credits"},
+				corrections: []parsers.Correction{
+					{Line: 1, Column: 6, From: "glb", To: "glob", Confidence: 0.75},
+				},
+			},
+		},
+		{
+			name: "when currency is defined should keep it",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "glob Gold is 10 Globs"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "glob Gold is 10 Globs"},
+			},
+		},
+		{
+			name: "when currencies of a rate are defined should keep them",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "1 Globs is 3 Proks"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "1 Globs is 3 Proks"},
+			},
+		},
+		{
+			name: "when word names a dialect should keep it",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "how much is Blobb: glb ?"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "how much is Blobb: glob ?"},
+				corrections: []parsers.Correction{
+					{Line: 1, Column: 20, From: "glb", To: "glob", Confidence: 0.75},
+				},
+			},
+		},
+		{
+			name: "when words name the dialects of several scopes should keep them",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "on Blobb on Prk how much is glb ?"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "on Blobb on Prk how much is glob ?"},
+				corrections: []parsers.Correction{
+					{Line: 1, Column: 29, From: "glb", To: "glob", Confidence: 0.75},
+				},
+			},
+		},
+		{
+			name: "when closest words are tied should keep the word",
+			args: args{
+				parser: parser,
+				param:  parsers.Line{Number: 1, Text: "how much is flob ?"},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Line{Number: 1, Text: "how much is flob ?"},
+			},
+		},
+		{
+			name: "when mode is strict should keep the line",
+			args: args{
+				parser: strictParser,
+				param:  parsers.Line{Number: 1, Text: "Istegj glob glb ?"},
 			},
 			beforeEach: func(t *testing.T, a *args) {},
 			want: want{
-				result: "is tegj glob glob smaller than glob prok ?",
+				result: parsers.Line{Number: 1, Text: "Istegj glob glb ?"},
 			},
 		},
-		// {
-		// 	name: "when input is invalid should return success",
-		// 	args: args{
-		// 		param: notAcceptedParams,
-		// 	},
-		// 	beforeEach: func(t *testing.T, a *args) {},
-		// 	want: want{
-		// 		result: false,
-		// 	},
-		// },
 	}
 
 	for _, tc := range testcases {
@@ -605,12 +927,2005 @@ func TestFixTypo(t *testing.T) {
 
 			tc.beforeEach(t, &tc.args)
 
-			result := parser.FixTypo(tc.args.param)
+			result, corrections := tc.args.parser.FixTypo(tc.args.param)
 
 			if diff := deep.Equal(result, tc.want.result); diff != nil {
 				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
 			}
+
+			if diff := deep.Equal(corrections, tc.want.corrections); diff != nil {
+				t.Errorf("got unexpected corrections.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.corrections, corrections, diff)
+			}
 		})
 
 	}
 }
+
+func TestReset(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	converter := mockConverter.NewMockConverterService(ctrl)
+	alienDictionary := map[string]string{
+		"glob": "i",
+	}
+	metalValue := map[string]rationals.Rational{
+		"gold": rationals.FromInt(14450),
+	}
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter:       converter,
+		AlienDictionary: alienDictionary,
+		MetalValue:      metalValue,
+	})
+
+	if diff := deep.Equal(parser.AlienDictionary(), alienDictionary); diff != nil {
+		t.Errorf("got unexpected dictionary.\n diff: %v\n", diff)
+	}
+
+	if diff := deep.Equal(parser.MetalValue(), metalValue); diff != nil {
+		t.Errorf("got unexpected metal value.\n diff: %v\n", diff)
+	}
+
+	parser.Reset()
+
+	if diff := deep.Equal(parser.AlienDictionary(), map[string]string{}); diff != nil {
+		t.Errorf("got unexpected dictionary after reset.\n diff: %v\n", diff)
+	}
+
+	if diff := deep.Equal(parser.MetalValue(), map[string]rationals.Rational{}); diff != nil {
+		t.Errorf("got unexpected metal value after reset.\n diff: %v\n", diff)
+	}
+}
+
+func TestParseMetalCommodities(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	converter := mockConverter.NewMockConverterService(ctrl)
+
+	type args struct {
+		allowList []string
+		denyList  []string
+		param     string
+	}
+
+	type want struct {
+		result bool
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when commodity is not a metal should learn it",
+			args: args{
+				param: "glob glob dirt is 12 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {
+				converter.
+					EXPECT().
+					AlienToRoman(gomock.Any(), []string{"glob", "glob"}).
+					Return("II", nil)
+
+				converter.
+					EXPECT().
+					RomanToArabic("II").
+					Return(2, nil)
+			},
+			want: want{
+				result: true,
+				error:  nil,
+			},
+		},
+		{
+			name: "when commodity is a known alien word should not learn it",
+			args: args{
+				param: "glob glob is 12 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  nil,
+			},
+		},
+		{
+			name: "when commodity is a reserved keyword should not learn it",
+			args: args{
+				param: "glob many is 12 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  nil,
+			},
+		},
+		{
+			name: "when commodity is denied should return error",
+			args: args{
+				denyList: []string{"Dirt"},
+				param:    "glob dirt is 12 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  errors.New("1:6: commodity 'dirt' is not allowed"),
+			},
+		},
+		{
+			name: "when commodity is not allowed should return error",
+			args: args{
+				allowList: []string{"Gold", "Silver"},
+				param:     "glob dirt is 12 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  errors.New("1:6: commodity 'dirt' is not allowed"),
+			},
+		},
+		{
+			name: "when alien number is missing should not learn it",
+			args: args{
+				param: "dirt is 12 credits",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: false,
+				error:  nil,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			parser := parsers.NewParser(parsers.NewParserParams{
+				Converter: converter,
+				AlienDictionary: map[string]string{
+					"glob": "i",
+				},
+				MetalValue:         map[string]rationals.Rational{},
+				CommodityAllowList: tc.args.allowList,
+				CommodityDenyList:  tc.args.denyList,
+			})
+
+			result, err := parser.ParseMetal(context.Background(), parsers.Line{Number: 1, Text: tc.args.param})
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestNumeralsDeclaration(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter:       converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{},
+		MetalValue:      map[string]rationals.Rational{},
+	})
+
+	type args struct {
+		statements []string
+		param      string
+	}
+
+	type want struct {
+		result []parsers.Answer
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when dictionary declares mayan numerals should read the words as mayan digits",
+			args: args{
+				statements: []string{"numerals are mayan", "glob is 𝋡", "prok is 𝋠"},
+				param:      "how much is glob prok ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob prok ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Operands: []parsers.Operand{{Alien: []string{"glob", "prok"}, Value: 20}},
+						Value:    rationals.FromInt(20),
+					},
+				},
+			},
+		},
+		{
+			name: "when dictionary declares babylonian numerals should read the words as babylonian symbols",
+			args: args{
+				statements: []string{"numerals are Babylonian", "glob is 𒁹", "prok is 𒌋", "pish is ."},
+				param:      "how much is glob pish prok glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob pish prok glob ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Operands: []parsers.Operand{{Alien: []string{"glob", "pish", "prok", "glob"}, Value: 71}},
+						Value:    rationals.FromInt(71),
+					},
+				},
+			},
+		},
+		{
+			name: "when symbol does not belong to the declared system should not learn the word",
+			args: args{
+				statements: []string{"numerals are attic", "glob is V"},
+				param:      "how much is glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Err:      errors.New("1:13: unknown alien word 'glob'"),
+						Category: parsers.ErrorCategoryUnknownAlienWord,
+					},
+				},
+			},
+		},
+		{
+			name: "when declared system is unknown should not learn it",
+			args: args{
+				statements: []string{"numerals are klingon", "glob is I"},
+				param:      "how much is glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Operands: []parsers.Operand{{Alien: []string{"glob"}, Value: 1}},
+						Value:    rationals.FromInt(1),
+					},
+				},
+				error: errors.New("1:14: unknown numeral system 'klingon'"),
+			},
+		},
+		{
+			name: "when system is declared on a dialect should not learn it",
+			args: args{
+				statements: []string{"on Vega numerals are attic", "glob is I"},
+				param:      "how much is glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Operands: []parsers.Operand{{Alien: []string{"glob"}, Value: 1}},
+						Value:    rationals.FromInt(1),
+					},
+				},
+				error: errors.New("1:4: numeral system cannot be declared on dialect 'Vega'"),
+			},
+		},
+		{
+			name: "when system is redeclared after words are learned should not learn it",
+			args: args{
+				statements: []string{"glob is I", "numerals are mayan"},
+				param:      "how much is glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Operands: []parsers.Operand{{Alien: []string{"glob"}, Value: 1}},
+						Value:    rationals.FromInt(1),
+					},
+				},
+				error: errors.New("2:14: numeral system cannot change once alien words are learned 'mayan'"),
+			},
+		},
+		{
+			name: "when same system is redeclared after words are learned should keep the words",
+			args: args{
+				statements: []string{"glob is I", "numerals are roman"},
+				param:      "how much is glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "how much is glob ?",
+						Kind:     parsers.QuestionKindHowMuch,
+						Operands: []parsers.Operand{{Alien: []string{"glob"}, Value: 1}},
+						Value:    rationals.FromInt(1),
+					},
+				},
+			},
+		},
+		{
+			name: "when system is unknown should return error",
+			args: args{
+				param: "numerals are egyptian",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []parsers.Answer{
+					{
+						Line:     1,
+						Question: "numerals are egyptian",
+						Kind:     parsers.QuestionKindUnknown,
+						Err:      errors.New("1:14: unknown numeral system 'egyptian'"),
+						Category: parsers.ErrorCategoryUnknown,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			parser.Reset()
+			var err error
+			for idx, statement := range tc.args.statements {
+				if _, statementErr := parser.ParseCurrency(context.Background(), parsers.Line{Number: idx + 1, Text: statement}); err == nil {
+					err = statementErr
+				}
+			}
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			result, _ := parser.ProcessQuestion(context.Background(), []parsers.Line{{Number: 1, Text: tc.args.param}})
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestReverseTranslation(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{
+			"glob": "i",
+			"prok": "v",
+			"pish": "x",
+			"tegj": "l",
+		},
+		MetalValue: map[string]rationals.Rational{
+			"Silver": rationals.FromInt(17),
+		},
+	})
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result parsers.Answer
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when number has alien words should say it",
+			args: args{
+				param: "how do you say 42 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how do you say 42 ?",
+					Kind:     parsers.QuestionKindSay,
+					Operands: []parsers.Operand{{Alien: []string{"pish", "tegj", "glob", "glob"}, Value: 42}},
+					Value:    rationals.FromInt(42),
+					Alien:    []string{"pish", "tegj", "glob", "glob"},
+				},
+			},
+		},
+		{
+			name: "when credits buy a whole amount of commodity should say the amount",
+			args: args{
+				param: "how many Silver for 68 Credits ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Silver for 68 Credits ?",
+					Kind:      parsers.QuestionKindHowManyFor,
+					Operands:  []parsers.Operand{{Alien: []string{"glob", "prok"}, Value: 4, Commodity: "Silver", Credits: rationals.FromInt(68)}},
+					Value:     rationals.FromInt(68),
+					Commodity: "Silver",
+					Alien:     []string{"glob", "prok"},
+				},
+			},
+		},
+		{
+			name: "when credits buy part of a unit should say the whole units",
+			args: args{
+				param: "how many Silver for 40 Credits ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Silver for 40 Credits ?",
+					Kind:      parsers.QuestionKindHowManyFor,
+					Operands:  []parsers.Operand{{Alien: []string{"glob", "glob"}, Value: 2, Commodity: "Silver", Credits: rationals.FromInt(34)}},
+					Value:     rationals.FromInt(40),
+					Commodity: "Silver",
+					Alien:     []string{"glob", "glob"},
+				},
+			},
+		},
+		{
+			name: "when symbol has no alien word should return error",
+			args: args{
+				param: "how do you say 90 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how do you say 90 ?",
+					Kind:     parsers.QuestionKindSay,
+					Err:      errors.New("1:16: no alien word for 'C'"),
+					Category: parsers.ErrorCategoryNoAlienWord,
+				},
+			},
+		},
+		{
+			name: "when credits do not buy a unit should return error",
+			args: args{
+				param: "how many Silver for 10 Credits ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how many Silver for 10 Credits ?",
+					Kind:     parsers.QuestionKindHowManyFor,
+					Err:      errors.New("1:21: number out of range '0'"),
+					Category: parsers.ErrorCategoryOutOfRange,
+				},
+			},
+		},
+		{
+			name: "when credits buy more units than an int should return error",
+			args: args{
+				param: "how many Silver for 184467440737095516180 Credits ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how many Silver for 184467440737095516180 Credits ?",
+					Kind:     parsers.QuestionKindHowManyFor,
+					Err:      errors.New("1:21: number out of range '10851025925711500951'"),
+					Category: parsers.ErrorCategoryOutOfRange,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, _ := parser.ProcessQuestion(context.Background(), []parsers.Line{{Number: 1, Text: tc.args.param}})
+
+			if diff := deep.Equal(result, []parsers.Answer{tc.want.result}); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestArithmeticQuestion(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{
+			"glob": "i",
+			"prok": "v",
+			"pish": "x",
+			"tegj": "l",
+		},
+		MetalValue: map[string]rationals.Rational{},
+	})
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result parsers.Answer
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when numbers are added should say the sum",
+			args: args{
+				param: "how much is pish pish plus glob prok ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is pish pish plus glob prok ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: []string{"pish", "pish"}, Value: 20}, {Alien: []string{"glob", "prok"}, Value: 4}},
+					Value:      rationals.FromInt(24),
+					Expression: "pish pish plus glob prok",
+					Alien:      []string{"pish", "pish", "glob", "prok"},
+				},
+			},
+		},
+		{
+			name: "when times follows minus should multiply first",
+			args: args{
+				param: "how much is tegj minus pish times glob glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is tegj minus pish times glob glob ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: []string{"tegj"}, Value: 50}, {Alien: []string{"pish"}, Value: 10}, {Alien: []string{"glob", "glob"}, Value: 2}},
+					Value:      rationals.FromInt(30),
+					Expression: "tegj minus pish times glob glob",
+					Alien:      []string{"pish", "pish", "pish"},
+				},
+			},
+		},
+		{
+			name: "when expression is parenthesized should evaluate it first",
+			args: args{
+				param: "how much is (tegj minus pish) times glob glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is (tegj minus pish) times glob glob ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: []string{"tegj"}, Value: 50}, {Alien: []string{"pish"}, Value: 10}, {Alien: []string{"glob", "glob"}, Value: 2}},
+					Value:      rationals.FromInt(80),
+					Expression: "(tegj minus pish) times glob glob",
+					Alien:      []string{"tegj", "pish", "pish", "pish"},
+				},
+			},
+		},
+		{
+			name: "when numbers are subtracted from left to right should say the difference",
+			args: args{
+				param: "how much is tegj minus pish minus pish ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is tegj minus pish minus pish ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: []string{"tegj"}, Value: 50}, {Alien: []string{"pish"}, Value: 10}, {Alien: []string{"pish"}, Value: 10}},
+					Value:      rationals.FromInt(30),
+					Expression: "tegj minus pish minus pish",
+					Alien:      []string{"pish", "pish", "pish"},
+				},
+			},
+		},
+		{
+			name: "when result is not positive should say the value without alien words",
+			args: args{
+				param: "how much is glob minus prok ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is glob minus prok ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: []string{"glob"}, Value: 1}, {Alien: []string{"prok"}, Value: 5}},
+					Value:      rationals.FromInt(-4),
+					Expression: "glob minus prok",
+				},
+			},
+		},
+		{
+			name: "when result has no alien word should return error",
+			args: args{
+				param: "how much is tegj times glob glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is tegj times glob glob ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Expression: "tegj times glob glob",
+					Err:        errors.New("1:13: no alien word for 'C'"),
+					Category:   parsers.ErrorCategoryNoAlienWord,
+				},
+			},
+		},
+		{
+			name: "when operand has unknown word should return error",
+			args: args{
+				param: "how much is glob plus prk ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is glob plus prk ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Expression: "glob plus prk",
+					Err:        errors.New("1:23: unknown alien word 'prk', did you mean 'prok'?"),
+					Category:   parsers.ErrorCategoryUnknownAlienWord,
+				},
+			},
+		},
+		{
+			name: "when parenthesis is not closed should return error",
+			args: args{
+				param: "how much is (glob plus prok ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how much is (glob plus prok ?",
+					Kind:     parsers.QuestionKindArithmetic,
+					Err:      errors.New("1:29: expected ')', found '?'"),
+					Category: parsers.ErrorCategoryMalformedQuestion,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, _ := parser.ProcessQuestion(context.Background(), []parsers.Line{{Number: 1, Text: tc.args.param}})
+
+			if diff := deep.Equal(result, []parsers.Answer{tc.want.result}); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestExchangeQuestion(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{
+			"glob": "i",
+			"prok": "v",
+			"pish": "x",
+		},
+		MetalValue: map[string]rationals.Rational{
+			"Silver": rationals.FromInt(17),
+			"Gold":   rationals.FromInt(14450),
+			"Iron":   rationals.New(391, 2),
+			"Dirt":   rationals.FromInt(0),
+		},
+	})
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result parsers.Answer
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when quantity is worth part of a unit should return the exact amount",
+			args: args{
+				param: "how many Gold is pish pish Silver ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Gold is pish pish Silver ?",
+					Kind:      parsers.QuestionKindExchange,
+					Operands:  []parsers.Operand{{Alien: []string{"pish", "pish"}, Value: 20, Commodity: "Silver", Credits: rationals.FromInt(340)}},
+					Value:     rationals.New(340, 14450),
+					Commodity: "Gold",
+				},
+			},
+		},
+		{
+			name: "when whole amount has no alien words should only return the number",
+			args: args{
+				param: "how many Silver for pish pish Iron ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Silver for pish pish Iron ?",
+					Kind:      parsers.QuestionKindExchange,
+					Operands:  []parsers.Operand{{Alien: []string{"pish", "pish"}, Value: 20, Commodity: "Iron", Credits: rationals.FromInt(3910)}},
+					Value:     rationals.FromInt(230),
+					Commodity: "Silver",
+				},
+			},
+		},
+		{
+			name: "when amount is a fraction should keep it exact",
+			args: args{
+				param: "how many Iron for pish Silver ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Iron for pish Silver ?",
+					Kind:      parsers.QuestionKindExchange,
+					Operands:  []parsers.Operand{{Alien: []string{"pish"}, Value: 10, Commodity: "Silver", Credits: rationals.FromInt(170)}},
+					Value:     rationals.New(20, 23),
+					Commodity: "Iron",
+				},
+			},
+		},
+		{
+			name: "when amount can be said should set the alien words",
+			args: args{
+				param: "how many Silver is glob Silver ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Silver is glob Silver ?",
+					Kind:      parsers.QuestionKindExchange,
+					Operands:  []parsers.Operand{{Alien: []string{"glob"}, Value: 1, Commodity: "Silver", Credits: rationals.FromInt(17)}},
+					Value:     rationals.FromInt(1),
+					Commodity: "Silver",
+					Alien:     []string{"glob"},
+				},
+			},
+		},
+		{
+			name: "when commodity is free should return error",
+			args: args{
+				param: "how many Dirt is glob Silver ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how many Dirt is glob Silver ?",
+					Kind:     parsers.QuestionKindExchange,
+					Err:      errors.New("1:10: number out of range 'Dirt'"),
+					Category: parsers.ErrorCategoryOutOfRange,
+				},
+			},
+		},
+		{
+			name: "when commodity is unknown should return error",
+			args: args{
+				param: "how many Wood is glob Silver ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how many Wood is glob Silver ?",
+					Kind:     parsers.QuestionKindExchange,
+					Err:      errors.New("1:10: unknown commodity 'Wood', did you mean 'Gold'?"),
+					Category: parsers.ErrorCategoryUnknownCommodity,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, _ := parser.ProcessQuestion(context.Background(), []parsers.Line{{Number: 1, Text: tc.args.param}})
+
+			if diff := deep.Equal(result, []parsers.Answer{tc.want.result}); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestExactPrices(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{
+			"glob": "i",
+			"prok": "v",
+		},
+		MetalValue: map[string]rationals.Rational{},
+	})
+
+	for idx, text := range []string{
+		"glob prok Gold is 57800 Credits",
+		"glob glob glob Silver is 100 Credits",
+		"glob Iron is 33.4 Credits",
+		"glob Dirt is 0.1 Credits",
+		"glob Wood is 100 Credits",
+	} {
+		if _, err := parser.ParseMetal(context.Background(), parsers.Line{Number: idx + 1, Text: text}); err != nil {
+			t.Fatalf("got unexpected error.\n actual: %v\n", err)
+		}
+	}
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result parsers.Answer
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when price is a fraction should keep it exact",
+			args: args{
+				param: "how many Credits is glob Gold ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Credits is glob Gold ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"glob"}, Value: 1, Commodity: "Gold", Credits: rationals.New(57800, 4)}},
+					Value:     rationals.New(57800, 4),
+					Commodity: "Gold",
+				},
+			},
+		},
+		{
+			name: "when credits differ by a fraction should not be equal",
+			args: args{
+				param: "does glob Iron has more Credits than glob Silver ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "does glob Iron has more Credits than glob Silver ?",
+					Kind:     parsers.QuestionKindDoes,
+					Operands: []parsers.Operand{
+						{Alien: []string{"glob"}, Value: 1, Commodity: "Iron", Credits: rationals.New(167, 5)},
+						{Alien: []string{"glob"}, Value: 1, Commodity: "Silver", Credits: rationals.New(100, 3)},
+					},
+					Comparison: parsers.ComparisonMore,
+				},
+			},
+		},
+		{
+			name: "when thirds add up to a whole should be equal",
+			args: args{
+				param: "does glob glob glob Silver has more Credits than glob Wood ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "does glob glob glob Silver has more Credits than glob Wood ?",
+					Kind:     parsers.QuestionKindDoes,
+					Operands: []parsers.Operand{
+						{Alien: []string{"glob", "glob", "glob"}, Value: 3, Commodity: "Silver", Credits: rationals.FromInt(100)},
+						{Alien: []string{"glob"}, Value: 1, Commodity: "Wood", Credits: rationals.FromInt(100)},
+					},
+					Comparison: parsers.ComparisonEqual,
+				},
+			},
+		},
+		{
+			name: "when decimal credits buy a whole amount should not lose a unit",
+			args: args{
+				param: "how many Dirt for 0.3 Credits ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Dirt for 0.3 Credits ?",
+					Kind:      parsers.QuestionKindHowManyFor,
+					Operands:  []parsers.Operand{{Alien: []string{"glob", "glob", "glob"}, Value: 3, Commodity: "Dirt", Credits: rationals.New(3, 10)}},
+					Value:     rationals.New(3, 10),
+					Commodity: "Dirt",
+					Alien:     []string{"glob", "glob", "glob"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, _ := parser.ProcessQuestion(context.Background(), []parsers.Line{{Number: 1, Text: tc.args.param}})
+
+			if diff := deep.Equal(result, []parsers.Answer{tc.want.result}); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestCurrencies(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{
+			"glob": "i",
+			"prok": "v",
+		},
+		MetalValue: map[string]rationals.Rational{},
+	})
+
+	for idx, text := range []string{
+		"1 Credits is 3 Zorbs",
+		"2 Zorbs is 5 Blips",
+		"1 Quids is 2 Pounds",
+	} {
+		if _, err := parser.ParseCurrency(context.Background(), parsers.Line{Number: idx + 1, Text: text}); err != nil {
+			t.Fatalf("got unexpected error.\n actual: %v\n", err)
+		}
+	}
+
+	for idx, text := range []string{
+		"glob prok Gold is 57800 Credits",
+		"glob glob Iron is 15 Blips",
+	} {
+		if _, err := parser.ParseMetal(context.Background(), parsers.Line{Number: idx + 1, Text: text}); err != nil {
+			t.Fatalf("got unexpected error.\n actual: %v\n", err)
+		}
+	}
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result parsers.Answer
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when currency has a rate to credits should convert the price",
+			args: args{
+				param: "how many Zorbs is glob prok Gold ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Zorbs is glob prok Gold ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"glob", "prok"}, Value: 4, Commodity: "Gold", Credits: rationals.FromInt(57800)}},
+					Value:     rationals.FromInt(173400),
+					Commodity: "Gold",
+					Currency:  "Zorbs",
+				},
+			},
+		},
+		{
+			name: "when currency is reached through another one should multiply the rates",
+			args: args{
+				param: "how many Blips is glob Gold ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Blips is glob Gold ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"glob"}, Value: 1, Commodity: "Gold", Credits: rationals.FromInt(14450)}},
+					Value:     rationals.FromInt(108375),
+					Commodity: "Gold",
+					Currency:  "Blips",
+				},
+			},
+		},
+		{
+			name: "when price is given in another currency should keep it in credits",
+			args: args{
+				param: "how many Credits is glob Iron ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Credits is glob Iron ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"glob"}, Value: 1, Commodity: "Iron", Credits: rationals.FromInt(1)}},
+					Value:     rationals.FromInt(1),
+					Commodity: "Iron",
+				},
+			},
+		},
+		{
+			name: "when currencies have no path of rates should return error",
+			args: args{
+				param: "how many Quids is glob Gold ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how many Quids is glob Gold ?",
+					Kind:     parsers.QuestionKindHowMany,
+					Err:      errors.New("1:10: no exchange rate from 'credits' to 'quids'"),
+					Category: parsers.ErrorCategoryNoExchangeRate,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, _ := parser.ProcessQuestion(context.Background(), []parsers.Line{{Number: 1, Text: tc.args.param}})
+
+			if diff := deep.Equal(result, []parsers.Answer{tc.want.result}); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestDialects(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{
+			"glob": "i",
+			"prok": "v",
+		},
+		MetalValue: map[string]rationals.Rational{},
+	})
+
+	for idx, text := range []string{
+		"on Vega glob is X",
+		"on Vega blip is I",
+		"Orion: zib is V",
+	} {
+		if _, err := parser.ParseCurrency(context.Background(), parsers.Line{Number: idx + 1, Text: text}); err != nil {
+			t.Fatalf("got unexpected error.\n actual: %v\n", err)
+		}
+	}
+
+	for idx, text := range []string{
+		"glob prok Gold is 57800 Credits",
+		"on Vega blip glob Silver is 153 Credits",
+	} {
+		if _, err := parser.ParseMetal(context.Background(), parsers.Line{Number: idx + 1, Text: text}); err != nil {
+			t.Fatalf("got unexpected error.\n actual: %v\n", err)
+		}
+	}
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result parsers.Answer
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when number is qualified should use the words of its dialect",
+			args: args{
+				param: "how much is Vega: glob glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how much is Vega: glob glob ?",
+					Kind:     parsers.QuestionKindHowMuch,
+					Operands: []parsers.Operand{{Alien: []string{"glob", "glob"}, Value: 20, Dialect: "vega"}},
+					Value:    rationals.FromInt(20),
+				},
+			},
+		},
+		{
+			name: "when expression is qualified should say the result in its dialect",
+			args: args{
+				param: "how much is Vega: glob plus Vega: blip ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is Vega: glob plus Vega: blip ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: []string{"glob"}, Value: 10, Dialect: "vega"}, {Alien: []string{"blip"}, Value: 1, Dialect: "vega"}},
+					Value:      rationals.FromInt(11),
+					Expression: "Vega: glob plus Vega: blip",
+					Alien:      []string{"glob", "blip"},
+				},
+			},
+		},
+		{
+			name: "when number is not qualified should use the default dialect",
+			args: args{
+				param: "how much is glob glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how much is glob glob ?",
+					Kind:     parsers.QuestionKindHowMuch,
+					Operands: []parsers.Operand{{Alien: []string{"glob", "glob"}, Value: 2}},
+					Value:    rationals.FromInt(2),
+				},
+			},
+		},
+		{
+			name: "when question is scoped to a dialect should use its words",
+			args: args{
+				param: "on Vega how much is blip glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "on Vega how much is blip glob ?",
+					Kind:     parsers.QuestionKindHowMuch,
+					Operands: []parsers.Operand{{Alien: []string{"blip", "glob"}, Value: 9, Dialect: "vega"}},
+					Value:    rationals.FromInt(9),
+				},
+			},
+		},
+		{
+			name: "when price is defined in a dialect should learn the price per unit",
+			args: args{
+				param: "how many Credits is Orion: zib Silver ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Credits is Orion: zib Silver ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"zib"}, Value: 5, Dialect: "orion", Commodity: "Silver", Credits: rationals.FromInt(85)}},
+					Value:     rationals.FromInt(85),
+					Commodity: "Silver",
+				},
+			},
+		},
+		{
+			name: "when number is said in a dialect should answer with its words",
+			args: args{
+				param: "on Vega how do you say 11 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "on Vega how do you say 11 ?",
+					Kind:     parsers.QuestionKindSay,
+					Operands: []parsers.Operand{{Alien: []string{"glob", "blip"}, Value: 11, Dialect: "vega"}},
+					Value:    rationals.FromInt(11),
+					Alien:    []string{"glob", "blip"},
+				},
+			},
+		},
+		{
+			name: "when expression is in a dialect should answer with its words",
+			args: args{
+				param: "how much is Vega: glob plus Vega: blip ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is Vega: glob plus Vega: blip ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: []string{"glob"}, Value: 10, Dialect: "vega"}, {Alien: []string{"blip"}, Value: 1, Dialect: "vega"}},
+					Value:      rationals.FromInt(11),
+					Expression: "Vega: glob plus Vega: blip",
+					Alien:      []string{"glob", "blip"},
+				},
+			},
+		},
+		{
+			name: "when number mixes words of two dialects should return error",
+			args: args{
+				param: "how much is Vega: glob prok ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how much is Vega: glob prok ?",
+					Kind:     parsers.QuestionKindHowMuch,
+					Err:      errors.New("1:24: mixed dialects, 'prok' is a word of the default dialect, not of dialect 'vega'"),
+					Category: parsers.ErrorCategoryMixedDialects,
+				},
+			},
+		},
+		{
+			name: "when expression mixes numbers of two dialects should return error",
+			args: args{
+				param: "how much is glob plus Vega: glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:       1,
+					Question:   "how much is glob plus Vega: glob ?",
+					Kind:       parsers.QuestionKindArithmetic,
+					Expression: "glob plus Vega: glob",
+					Err:        errors.New("1:29: mixed dialects, 'Vega: glob' is in dialect 'vega', not in the default dialect"),
+					Category:   parsers.ErrorCategoryMixedDialects,
+				},
+			},
+		},
+		{
+			name: "when dialect is unknown should return error with suggestion",
+			args: args{
+				param: "how much is Vegas: glob ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how much is Vegas: glob ?",
+					Kind:     parsers.QuestionKindHowMuch,
+					Err:      errors.New("1:13: unknown dialect 'Vegas', did you mean 'vega'?"),
+					Category: parsers.ErrorCategoryUnknownDialect,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, _ := parser.ProcessQuestion(context.Background(), []parsers.Line{{Number: 1, Text: tc.args.param}})
+
+			if diff := deep.Equal(result, []parsers.Answer{tc.want.result}); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestPriceHistory(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter: converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{
+			"glob": "i",
+			"prok": "v",
+		},
+		MetalValue: map[string]rationals.Rational{},
+	})
+
+	for idx, text := range []string{
+		"glob glob Gold is 28000 Credits",
+		"on 3021-05-01 glob prok Gold is 57800 Credits",
+		"on 3021-07-01 glob Gold is 15000 Credits",
+		"on 3021-06-01 glob Gold is 14000 Credits",
+		"on 3021-06-01 glob Iron is 200 Credits",
+	} {
+		if _, err := parser.ParseMetal(context.Background(), parsers.Line{Number: idx + 1, Text: text}); err != nil {
+			t.Fatalf("got unexpected error.\n actual: %v\n", err)
+		}
+	}
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result parsers.Answer
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when question has no date should use the latest price",
+			args: args{
+				param: "how many Credits is glob Gold ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Credits is glob Gold ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"glob"}, Value: 1, Commodity: "Gold", Credits: rationals.FromInt(15000)}},
+					Value:     rationals.FromInt(15000),
+					Commodity: "Gold",
+				},
+			},
+		},
+		{
+			name: "when question is as of a date should use the price effective at that date",
+			args: args{
+				param: "how many Credits is glob Gold as of 3021-06-15 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Credits is glob Gold as of 3021-06-15 ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"glob"}, Value: 1, Commodity: "Gold", Credits: rationals.FromInt(14000)}},
+					Value:     rationals.FromInt(14000),
+					Commodity: "Gold",
+					AsOf:      "3021-06-15",
+				},
+			},
+		},
+		{
+			name: "when question is scoped to a date should use the price effective at that date",
+			args: args{
+				param: "on 3021-05-01 how many Credits is glob Gold ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "on 3021-05-01 how many Credits is glob Gold ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"glob"}, Value: 1, Commodity: "Gold", Credits: rationals.FromInt(14450)}},
+					Value:     rationals.FromInt(14450),
+					Commodity: "Gold",
+					AsOf:      "3021-05-01",
+				},
+			},
+		},
+		{
+			name: "when date is before every dated price should use the price without date",
+			args: args{
+				param: "how many Credits is glob Gold as of 3021-01-01 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:      1,
+					Question:  "how many Credits is glob Gold as of 3021-01-01 ?",
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: []string{"glob"}, Value: 1, Commodity: "Gold", Credits: rationals.FromInt(14000)}},
+					Value:     rationals.FromInt(14000),
+					Commodity: "Gold",
+					AsOf:      "3021-01-01",
+				},
+			},
+		},
+		{
+			name: "when comparison is as of a date should price both quantities at that date",
+			args: args{
+				param: "does glob Iron has less Credits than glob Gold as of 3021-06-01 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "does glob Iron has less Credits than glob Gold as of 3021-06-01 ?",
+					Kind:     parsers.QuestionKindDoes,
+					Operands: []parsers.Operand{
+						{Alien: []string{"glob"}, Value: 1, Commodity: "Iron", Credits: rationals.FromInt(200)},
+						{Alien: []string{"glob"}, Value: 1, Commodity: "Gold", Credits: rationals.FromInt(14000)},
+					},
+					Comparison: parsers.ComparisonLess,
+					AsOf:       "3021-06-01",
+				},
+			},
+		},
+		{
+			name: "when commodity is only priced later should return error",
+			args: args{
+				param: "how many Credits is glob Iron as of 3021-05-31 ?",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: parsers.Answer{
+					Line:     1,
+					Question: "how many Credits is glob Iron as of 3021-05-31 ?",
+					Kind:     parsers.QuestionKindHowMany,
+					AsOf:     "3021-05-31",
+					Err:      errors.New("1:37: no price of 'Iron' as of 3021-05-31"),
+					Category: parsers.ErrorCategoryNoPrice,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, _ := parser.ProcessQuestion(context.Background(), []parsers.Line{{Number: 1, Text: tc.args.param}})
+
+			if diff := deep.Equal(result, []parsers.Answer{tc.want.result}); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+
+	// the latest price is the current one, whatever the order of the definitions
+	metalValue := parser.MetalValue()
+	expected := map[string]rationals.Rational{"Gold": rationals.FromInt(15000), "Iron": rationals.FromInt(200)}
+	if diff := deep.Equal(metalValue, expected); diff != nil {
+		t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", expected, metalValue, diff)
+	}
+}
+
+func TestKnowledge(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter:       converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{"glob": "i"},
+		MetalValue:      map[string]rationals.Rational{"gold": rationals.FromInt(10)},
+	})
+
+	type args struct {
+		knowledge storages.KnowledgeBase
+	}
+
+	type want struct {
+		result storages.KnowledgeBase
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when knowledge declares a numeral system should restore it",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Numerals:   "mayan",
+					Dictionary: map[string]string{"prok": "𝋥"},
+					Prices:     map[string]rationals.Rational{"dirt": rationals.New(1, 10)},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Numerals:   "mayan",
+					Dictionary: map[string]string{"prok": "𝋥"},
+					Prices:     map[string]rationals.Rational{"dirt": rationals.New(1, 10)},
+				},
+			},
+		},
+		{
+			name: "when knowledge has the default numeral system should leave it empty",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+				},
+			},
+		},
+		{
+			name: "when numeral system is unknown should return error and keep the knowledge",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Numerals: "klingon",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+				},
+				error: errors.New("unknown numeral system 'klingon'"),
+			},
+		},
+		{
+			name: "when knowledge has rates should restore them from the first currency",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Rates:      []storages.Rate{{From: "zorbs", To: "credits", Rate: rationals.New(1, 3)}},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					Rates:      []storages.Rate{{From: "credits", To: "zorbs", Rate: rationals.FromInt(3)}},
+				},
+			},
+		},
+		{
+			name: "when rate is zero should return error and keep the knowledge",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Rates: []storages.Rate{{From: "credits", To: "blips", Rate: rationals.FromInt(0)}},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					Rates:      []storages.Rate{{From: "credits", To: "zorbs", Rate: rationals.FromInt(3)}},
+				},
+				error: errors.New("invalid knowledge base: rate of 'credits' to 'blips' is 0"),
+			},
+		},
+		{
+			name: "when knowledge has a price history should restore it",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					History:    map[string]map[string]rationals.Rational{"gold": {"3021-05-01": rationals.FromInt(14450)}},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					History:    map[string]map[string]rationals.Rational{"gold": {"3021-05-01": rationals.FromInt(14450)}},
+				},
+			},
+		},
+		{
+			name: "when price is not dated by a date should return error and keep the knowledge",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					History: map[string]map[string]rationals.Rational{"gold": {"yesterday": rationals.FromInt(14450)}},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					History:    map[string]map[string]rationals.Rational{"gold": {"3021-05-01": rationals.FromInt(14450)}},
+				},
+				error: errors.New("invalid knowledge base: price of 'gold' is dated 'yesterday'"),
+			},
+		},
+		{
+			name: "when symbol is out of the numeral system should return error and keep the knowledge",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "Z"},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					History:    map[string]map[string]rationals.Rational{"gold": {"3021-05-01": rationals.FromInt(14450)}},
+				},
+				error: errors.New("invalid knowledge base: symbol of 'glob' is 'Z'"),
+			},
+		},
+		{
+			name: "when symbol of a dialect is out of the numeral system should return error and keep the knowledge",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Dialects: map[string]map[string]string{"vega": {"zib": "𝋥"}},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					History:    map[string]map[string]rationals.Rational{"gold": {"3021-05-01": rationals.FromInt(14450)}},
+				},
+				error: errors.New("invalid knowledge base: symbol of 'zib' is '𝋥'"),
+			},
+		},
+		{
+			name: "when price is negative should return error and keep the knowledge",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Prices: map[string]rationals.Rational{"gold": rationals.FromInt(-3)},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					History:    map[string]map[string]rationals.Rational{"gold": {"3021-05-01": rationals.FromInt(14450)}},
+				},
+				error: errors.New("invalid knowledge base: price of 'gold' is -3"),
+			},
+		},
+		{
+			name: "when dated price is zero should return error and keep the knowledge",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					History: map[string]map[string]rationals.Rational{"gold": {"3021-06-01": rationals.FromInt(0)}},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					History:    map[string]map[string]rationals.Rational{"gold": {"3021-05-01": rationals.FromInt(14450)}},
+				},
+				error: errors.New("invalid knowledge base: price of 'gold' on '3021-06-01' is 0"),
+			},
+		},
+		{
+			name: "when knowledge has dialects should restore their words",
+			args: args{
+				knowledge: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Dialects:   map[string]map[string]string{"Vega": {"glob": "x"}},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{},
+					Dialects:   map[string]map[string]string{"vega": {"glob": "x"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			err := parser.Restore(tc.args.knowledge)
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			result := parser.Knowledge()
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestConflicts(t *testing.T) {
+
+	statements := []string{
+		"glob is i",
+		"glob glob gold is 20 credits",
+		"glob is v",
+		"prok is i",
+		"glob gold is 12 credits",
+		"1 credits is 3 zorbs",
+		"2 zorbs is 1 credits",
+	}
+
+	type args struct {
+		policy   parsers.ConflictPolicy
+		rounding rationals.Rounding
+	}
+
+	type want struct {
+		dictionary map[string]string
+		prices     map[string]rationals.Rational
+		conflicts  []string
+		error      error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when policy is warn should learn the last definitions and report them",
+			args: args{
+				policy: parsers.ConflictWarn,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				dictionary: map[string]string{"glob": "v", "prok": "i"},
+				prices:     map[string]rationals.Rational{"gold": rationals.New(12, 5)},
+				conflicts: []string{
+					`input:3:1: 'glob' is redefined as 'v' by "glob is v", it was 'i' by input:1:1 "glob is i", the last definition is kept`,
+					`input:5:6: price of 'gold' is redefined as 2.4 Credits by "glob gold is 12 credits", it was 10 Credits by input:2:11 "glob glob gold is 20 credits", the last definition is kept`,
+					`input:7:3: rate of 'zorbs' to 'credits' is redefined as 0.5 by "2 zorbs is 1 credits", it was 0.3 by input:6:3 "1 credits is 3 zorbs", the last definition is kept`,
+				},
+			},
+		},
+		{
+			name: "when policy is first wins should keep the first definitions",
+			args: args{
+				policy: parsers.ConflictFirstWins,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				dictionary: map[string]string{"glob": "i"},
+				prices:     map[string]rationals.Rational{"gold": rationals.FromInt(10)},
+				conflicts: []string{
+					`input:3:1: 'glob' is redefined as 'v' by "glob is v", it was 'i' by input:1:1 "glob is i", the first definition is kept`,
+					`input:4:1: 'prok' stands for 'i' like 'glob' by "prok is i", defined by input:1:1 "glob is i", the first definition is kept`,
+					`input:5:6: price of 'gold' is redefined as 12 Credits by "glob gold is 12 credits", it was 10 Credits by input:2:11 "glob glob gold is 20 credits", the first definition is kept`,
+					`input:7:3: rate of 'zorbs' to 'credits' is redefined as 0.5 by "2 zorbs is 1 credits", it was 0.3 by input:6:3 "1 credits is 3 zorbs", the first definition is kept`,
+				},
+			},
+		},
+		{
+			name: "when rounding is exact should write the prices and rates as decimal numbers",
+			args: args{
+				policy:   parsers.ConflictLastWins,
+				rounding: rationals.Rounding{Mode: rationals.RoundExact, Precision: 3},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				dictionary: map[string]string{"glob": "v", "prok": "i"},
+				prices:     map[string]rationals.Rational{"gold": rationals.New(12, 5)},
+				conflicts: []string{
+					`input:3:1: 'glob' is redefined as 'v' by "glob is v", it was 'i' by input:1:1 "glob is i", the last definition is kept`,
+					`input:5:6: price of 'gold' is redefined as 2.4 Credits by "glob gold is 12 credits", it was 10 Credits by input:2:11 "glob glob gold is 20 credits", the last definition is kept`,
+					`input:7:3: rate of 'zorbs' to 'credits' is redefined as 0.5 by "2 zorbs is 1 credits", it was 0.333 by input:6:3 "1 credits is 3 zorbs", the last definition is kept`,
+				},
+			},
+		},
+		{
+			name: "when policy is error should reject the first conflict",
+			args: args{
+				policy: parsers.ConflictError,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				dictionary: map[string]string{"glob": "i"},
+				prices:     map[string]rationals.Rational{"gold": rationals.FromInt(10)},
+				conflicts: []string{
+					`input:3:1: 'glob' is redefined as 'v' by "glob is v", it was 'i' by input:1:1 "glob is i", the definition is rejected`,
+				},
+				error: errors.New(`input:3:1: conflicting definition, 'glob' is redefined as 'v' by "glob is v", it was 'i' by input:1:1 "glob is i"`),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			parser := parsers.NewParser(parsers.NewParserParams{
+				Converter:       converters.NewConverter(converters.NewConverterParams{}),
+				AlienDictionary: map[string]string{},
+				MetalValue:      map[string]rationals.Rational{},
+				ConflictPolicy:  tc.args.policy,
+				Rounding:        tc.args.rounding,
+			})
+
+			var err error
+			for idx, statement := range statements {
+				line := parsers.Line{File: "input", Number: idx + 1, Text: statement}
+
+				var found bool
+				found, err = parser.ParseCurrency(context.Background(), line)
+				if err == nil && !found {
+					_, err = parser.ParseMetal(context.Background(), line)
+				}
+				if err != nil {
+					break
+				}
+			}
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			if diff := deep.Equal(parser.AlienDictionary(), tc.want.dictionary); diff != nil {
+				t.Errorf("got unexpected dictionary.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.dictionary, parser.AlienDictionary(), diff)
+			}
+
+			if diff := deep.Equal(parser.MetalValue(), tc.want.prices); diff != nil {
+				t.Errorf("got unexpected prices.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.prices, parser.MetalValue(), diff)
+			}
+
+			conflicts := []string{}
+			for _, conflict := range parser.Conflicts() {
+				conflicts = append(conflicts, conflict.String())
+			}
+
+			if diff := deep.Equal(conflicts, tc.want.conflicts); diff != nil {
+				t.Errorf("got unexpected conflicts.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.conflicts, conflicts, diff)
+			}
+		})
+
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+
+	parser := parsers.NewParser(parsers.NewParserParams{
+		Converter:       converters.NewConverter(converters.NewConverterParams{}),
+		AlienDictionary: map[string]string{"glob": "i"},
+		MetalValue:      map[string]rationals.Rational{},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	type want struct {
+		found   bool
+		answers []parsers.Answer
+		error   error
+	}
+
+	testcases := []struct {
+		name string
+		run  func() (bool, []parsers.Answer, error)
+		want want
+	}{
+		{
+			name: "when context is canceled should not learn any word",
+			run: func() (bool, []parsers.Answer, error) {
+				found, err := parser.ParseCurrency(ctx, parsers.Line{Number: 1, Text: "prok is v"})
+				return found, nil, err
+			},
+			want: want{
+				error: context.Canceled,
+			},
+		},
+		{
+			name: "when context is canceled should not learn any price",
+			run: func() (bool, []parsers.Answer, error) {
+				found, err := parser.ParseMetal(ctx, parsers.Line{Number: 1, Text: "glob gold is 10 credits"})
+				return found, nil, err
+			},
+			want: want{
+				error: context.Canceled,
+			},
+		},
+		{
+			name: "when context is canceled should not answer any question",
+			run: func() (bool, []parsers.Answer, error) {
+				answers, err := parser.ProcessQuestion(ctx, []parsers.Line{{Number: 1, Text: "how much is glob ?"}})
+				return false, answers, err
+			},
+			want: want{
+				answers: []parsers.Answer{},
+				error:   context.Canceled,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			found, answers, err := tc.run()
+			if !errors.Is(err, tc.want.error) {
+				t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n", tc.want.error, err)
+			}
+
+			if diff := deep.Equal(found, tc.want.found); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.found, found, diff)
+			}
+
+			if diff := deep.Equal(answers, tc.want.answers); diff != nil {
+				t.Errorf("got unexpected answers.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.answers, answers, diff)
+			}
+		})
+
+	}
+
+	if diff := deep.Equal(parser.AlienDictionary(), map[string]string{"glob": "i"}); diff != nil {
+		t.Errorf("got unexpected dictionary.\n diff: %v\n", diff)
+	}
+}
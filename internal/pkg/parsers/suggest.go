@@ -0,0 +1,69 @@
+package parsers
+
+import (
+	"sort"
+)
+
+// maxSuggestionDistance is the largest edit distance of a suggested word.
+const maxSuggestionDistance = 2
+
+// suggest returns the candidate closest to word, or an empty string when none is close enough.
+func suggest(word string, candidates []string) string {
+	sorted := append([]string{}, candidates...)
+	sort.Strings(sorted)
+
+	best := ""
+	bestDistance := maxSuggestionDistance + 1
+	for _, candidate := range sorted {
+		distance := editDistance(word, candidate)
+		if distance < bestDistance && distance < len([]rune(word)) {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the number of single character insertions, deletions,
+// substitutions and transpositions of adjacent characters needed to turn a into b.
+func editDistance(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	distances := make([][]int, len(ra)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(rb)+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			distances[i][j] = minInt(distances[i-1][j]+1, distances[i][j-1]+1, distances[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				distances[i][j] = minInt(distances[i][j], distances[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return distances[len(ra)][len(rb)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,228 @@
+package rationals
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Rational is an exact fraction such as a price of 57800/6 credits, the zero
+// value is 0. A Rational is never modified once created.
+type Rational struct {
+	rat *big.Rat
+}
+
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds half away from zero, 0.25 is 0.3.
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds half to the even digit, 0.25 is 0.2.
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundDown truncates toward zero, 0.29 is 0.2.
+	RoundDown RoundingMode = "down"
+	// RoundUp rounds away from zero, 0.21 is 0.3.
+	RoundUp RoundingMode = "up"
+	// RoundExact never rounds, 1/3 is written as the fraction "1/3".
+	RoundExact RoundingMode = "exact"
+)
+
+// Rounding is the way a Rational is written as a decimal number.
+type Rounding struct {
+	Mode RoundingMode
+	// Precision is the largest number of decimals, trailing zeros are dropped.
+	Precision int
+}
+
+// DefaultRounding drops the decimal of whole numbers and keeps one otherwise.
+var DefaultRounding = Rounding{Mode: RoundHalfUp, Precision: 1}
+
+var (
+	ErrInvalidRational     = errors.New("invalid number")
+	ErrUnknownRoundingMode = errors.New("unknown rounding mode")
+)
+
+// New returns num/den, den must not be 0.
+func New(num int64, den int64) Rational {
+	return Rational{rat: big.NewRat(num, den)}
+}
+
+// FromInt returns n.
+func FromInt(n int) Rational {
+	return New(int64(n), 1)
+}
+
+// Parse reads a decimal number such as "12.5" or a fraction such as "25/2".
+func Parse(text string) (Rational, error) {
+	rat, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return Rational{}, fmt.Errorf("%w '%s'", ErrInvalidRational, text)
+	}
+
+	return Rational{rat: rat}, nil
+}
+
+// ParseRoundingMode returns the rounding mode named name.
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	switch mode := RoundingMode(name); mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp, RoundExact:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w '%s'", ErrUnknownRoundingMode, name)
+	}
+}
+
+func (r Rational) value() *big.Rat {
+	if r.rat == nil {
+		return new(big.Rat)
+	}
+
+	return r.rat
+}
+
+func (r Rational) Add(o Rational) Rational {
+	return Rational{rat: new(big.Rat).Add(r.value(), o.value())}
+}
+
+func (r Rational) Sub(o Rational) Rational {
+	return Rational{rat: new(big.Rat).Sub(r.value(), o.value())}
+}
+
+func (r Rational) Mul(o Rational) Rational {
+	return Rational{rat: new(big.Rat).Mul(r.value(), o.value())}
+}
+
+// Quo returns r/o, o must not be 0.
+func (r Rational) Quo(o Rational) Rational {
+	return Rational{rat: new(big.Rat).Quo(r.value(), o.value())}
+}
+
+// Cmp returns -1, 0 or +1 as r is less than, equal to or greater than o.
+func (r Rational) Cmp(o Rational) int {
+	return r.value().Cmp(o.value())
+}
+
+// Equal reports whether r and o are the same number, whatever their fractions.
+func (r Rational) Equal(o Rational) bool {
+	return r.Cmp(o) == 0
+}
+
+func (r Rational) Sign() int {
+	return r.value().Sign()
+}
+
+func (r Rational) IsInt() bool {
+	return r.value().IsInt()
+}
+
+// Floor returns the largest integer not greater than r, ok is false when it
+// does not fit in an int.
+func (r Rational) Floor() (int, bool) {
+	value := r.value()
+
+	// Div is the euclidean division, it rounds down as the denominator is positive
+	floor := new(big.Int).Div(value.Num(), value.Denom())
+	if !floor.IsInt64() || int64(int(floor.Int64())) != floor.Int64() {
+		return 0, false
+	}
+
+	return int(floor.Int64()), true
+}
+
+// Float64 returns the nearest float64 of r, for output that cannot be exact.
+func (r Rational) Float64() float64 {
+	f, _ := r.value().Float64()
+
+	return f
+}
+
+// String returns the exact fraction, "28900/3" or "14450".
+func (r Rational) String() string {
+	return r.value().RatString()
+}
+
+// Round returns r rounded to precision decimals.
+func (r Rational) Round(precision int, mode RoundingMode) Rational {
+	if precision < 0 {
+		precision = 0
+	}
+
+	value := r.value()
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+
+	num := new(big.Int).Mul(new(big.Int).Abs(value.Num()), scale)
+	quo, rem := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+
+	if rem.Sign() != 0 && roundsAway(quo, rem, value.Denom(), mode) {
+		quo.Add(quo, big.NewInt(1))
+	}
+
+	if value.Sign() < 0 {
+		quo.Neg(quo)
+	}
+
+	return Rational{rat: new(big.Rat).SetFrac(quo, scale)}
+}
+
+// roundsAway reports whether quo+rem/den is rounded away from zero.
+func roundsAway(quo *big.Int, rem *big.Int, den *big.Int, mode RoundingMode) bool {
+	half := new(big.Int).Mul(rem, big.NewInt(2)).Cmp(den)
+
+	switch mode {
+	case RoundDown:
+		return false
+	case RoundUp:
+		return true
+	case RoundHalfEven:
+		return half > 0 || (half == 0 && quo.Bit(0) == 1)
+	default:
+		return half >= 0
+	}
+}
+
+// Format writes value rounded by r as a decimal number without trailing zeros,
+// or as its exact fraction with RoundExact.
+func (r Rounding) Format(value Rational) string {
+	if r.Mode == RoundExact {
+		return value.String()
+	}
+
+	precision := r.Precision
+	if precision < 0 {
+		precision = 0
+	}
+
+	text := value.Round(precision, r.Mode).value().FloatString(precision)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+
+	return text
+}
+
+// Decimal returns r, or rounding half up to the same precision when r is
+// exact, for the output that only accepts decimal numbers.
+func (r Rounding) Decimal() Rounding {
+	if r.Mode == RoundExact {
+		return Rounding{Mode: RoundHalfUp, Precision: r.Precision}
+	}
+
+	return r
+}
+
+// MarshalText writes the exact fraction so that a stored price is never rounded.
+func (r Rational) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rational) UnmarshalText(text []byte) error {
+	value, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = value
+
+	return nil
+}
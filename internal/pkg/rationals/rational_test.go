@@ -0,0 +1,268 @@
+package rationals_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/go-test/deep"
+)
+
+func TestRoundingFormat(t *testing.T) {
+
+	type args struct {
+		value    rationals.Rational
+		rounding rationals.Rounding
+	}
+
+	type want struct {
+		result string
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when value is whole should drop the decimals",
+			args: args{
+				value:    rationals.FromInt(28900),
+				rounding: rationals.DefaultRounding,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "28900",
+			},
+		},
+		{
+			name: "when value is a fraction should keep the precision",
+			args: args{
+				value:    rationals.New(57800, 6),
+				rounding: rationals.Rounding{Mode: rationals.RoundHalfUp, Precision: 4},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "9633.3333",
+			},
+		},
+		{
+			name: "when value is half with half up should round away from zero",
+			args: args{
+				value:    rationals.New(-25, 100),
+				rounding: rationals.Rounding{Mode: rationals.RoundHalfUp, Precision: 1},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "-0.3",
+			},
+		},
+		{
+			name: "when value is half with half even should round to the even digit",
+			args: args{
+				value:    rationals.New(25, 100),
+				rounding: rationals.Rounding{Mode: rationals.RoundHalfEven, Precision: 1},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "0.2",
+			},
+		},
+		{
+			name: "when rounding down should truncate",
+			args: args{
+				value:    rationals.New(29, 100),
+				rounding: rationals.Rounding{Mode: rationals.RoundDown, Precision: 1},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "0.2",
+			},
+		},
+		{
+			name: "when rounding up should round away from zero",
+			args: args{
+				value:    rationals.New(21, 100),
+				rounding: rationals.Rounding{Mode: rationals.RoundUp, Precision: 1},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "0.3",
+			},
+		},
+		{
+			name: "when rounded decimals are zeros should drop them",
+			args: args{
+				value:    rationals.New(80155, 10),
+				rounding: rationals.Rounding{Mode: rationals.RoundHalfUp, Precision: 3},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "8015.5",
+			},
+		},
+		{
+			name: "when rounding is exact should write the fraction",
+			args: args{
+				value:    rationals.New(20, 23),
+				rounding: rationals.Rounding{Mode: rationals.RoundExact, Precision: 1},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "20/23",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result := tc.args.rounding.Format(tc.args.value)
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestParse(t *testing.T) {
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result rationals.Rational
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when number is a decimal should return its exact value",
+			args: args{
+				param: "0.1",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: rationals.New(1, 10),
+			},
+		},
+		{
+			name: "when number is a fraction should return its value",
+			args: args{
+				param: "25/2",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: rationals.New(125, 10),
+			},
+		},
+		{
+			name: "when number is invalid should return error",
+			args: args{
+				param: "57800a",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid number '57800a'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, err := rationals.Parse(tc.args.param)
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestFloor(t *testing.T) {
+
+	type args struct {
+		param rationals.Rational
+	}
+
+	type want struct {
+		result int
+		ok     bool
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when number is a fraction should round it down",
+			args: args{
+				param: rationals.New(28900, 3),
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: 9633,
+				ok:     true,
+			},
+		},
+		{
+			name: "when number is negative should round it toward minus infinity",
+			args: args{
+				param: rationals.New(-5, 2),
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: -3,
+				ok:     true,
+			},
+		},
+		{
+			name: "when number does not fit in an int should not be ok",
+			args: args{},
+			beforeEach: func(t *testing.T, a *args) {
+				a.param, _ = rationals.Parse("18446744073709551618")
+			},
+			want: want{
+				result: 0,
+				ok:     false,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, ok := tc.args.param.Floor()
+
+			if diff := deep.Equal([]interface{}{result, ok}, []interface{}{tc.want.result, tc.want.ok}); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v %v\n actual: %v %v\n diff: %v\n", tc.want.result, tc.want.ok, result, ok, diff)
+			}
+		})
+
+	}
+}
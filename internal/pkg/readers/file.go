@@ -2,12 +2,21 @@ package readers
 
 import (
 	"bufio"
+	"io"
 	"os"
 	"strings"
 )
 
+// Stdin is the file location that makes Open read from the standard input.
+const Stdin = "-"
+
+// maxLineSize caps the length of a single line handled by ReadLines.
+const maxLineSize = 1024 * 1024
+
 type FileService interface {
 	ReadFile(fileLoc string) ([]string, error)
+	Open(fileLoc string) (io.ReadCloser, error)
+	ReadLines(r io.Reader, fn func(line string) error) error
 }
 
 type file struct{}
@@ -20,23 +29,55 @@ func NewFile() *file {
 
 func (f *file) ReadFile(fileLoc string) ([]string, error) {
 
-	file, err := os.Open(fileLoc)
+	file, err := f.Open(fileLoc)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
-	fileScanner := bufio.NewScanner(file)
-	fileScanner.Split(bufio.ScanLines)
 	var fileLines []string
+	err = f.ReadLines(file, func(line string) error {
+		fileLines = append(fileLines, NormalizeLine(line))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fileLines, nil
+}
+
+// Open opens fileLoc for reading, or the standard input when fileLoc is Stdin.
+func (f *file) Open(fileLoc string) (io.ReadCloser, error) {
+	if fileLoc == Stdin {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	return os.Open(fileLoc)
+}
+
+// ReadLines streams r line by line, calling fn with every line as written,
+// NormalizeLine gives the text to parse. Reading stops at the first error
+// returned by fn.
+func (f *file) ReadLines(r io.Reader, fn func(line string) error) error {
+
+	fileScanner := bufio.NewScanner(r)
+	fileScanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	fileScanner.Split(bufio.ScanLines)
 
 	for fileScanner.Scan() {
-		line := fileScanner.Text()
-		line = strings.TrimSpace(line)
-		line = strings.ToLower(line)
-		fileLines = append(fileLines, line)
+		if err := fn(fileScanner.Text()); err != nil {
+			return err
+		}
 	}
 
-	file.Close()
+	return fileScanner.Err()
+}
+
+// NormalizeLine trims and lowercases line into the text the parser reads.
+func NormalizeLine(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.ToLower(line)
 
-	return fileLines, nil
+	return line
 }
@@ -0,0 +1,80 @@
+package readers_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/readers"
+	"github.com/go-test/deep"
+)
+
+func TestReadLines(t *testing.T) {
+
+	reader := readers.NewFile()
+
+	type args struct {
+		param string
+		fnErr error
+	}
+
+	type want struct {
+		result []string
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when input is valid should return lines as written",
+			args: args{
+				param: "  Glob is I \nHow much is glob ?\n",
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []string{"  Glob is I ", "How much is glob ?"},
+				error:  nil,
+			},
+		},
+		{
+			name: "when callback is error should stop reading",
+			args: args{
+				param: "glob is I\nprok is V\n",
+				fnErr: errors.New("error"),
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: []string{"glob is I"},
+				error:  errors.New("error"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result := []string{}
+			err := reader.ReadLines(strings.NewReader(tc.args.param), func(line string) error {
+				result = append(result, line)
+				return tc.args.fnErr
+			})
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
@@ -5,6 +5,7 @@
 package mock_readers
 
 import (
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -33,6 +34,21 @@ func (m *MockFileService) EXPECT() *MockFileServiceMockRecorder {
 	return m.recorder
 }
 
+// Open mocks base method.
+func (m *MockFileService) Open(fileLoc string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", fileLoc)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockFileServiceMockRecorder) Open(fileLoc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockFileService)(nil).Open), fileLoc)
+}
+
 // ReadFile mocks base method.
 func (m *MockFileService) ReadFile(fileLoc string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -47,3 +63,17 @@ func (mr *MockFileServiceMockRecorder) ReadFile(fileLoc interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadFile", reflect.TypeOf((*MockFileService)(nil).ReadFile), fileLoc)
 }
+
+// ReadLines mocks base method.
+func (m *MockFileService) ReadLines(r io.Reader, fn func(string) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadLines", r, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReadLines indicates an expected call of ReadLines.
+func (mr *MockFileServiceMockRecorder) ReadLines(r, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadLines", reflect.TypeOf((*MockFileService)(nil).ReadLines), r, fn)
+}
@@ -0,0 +1,51 @@
+package renderers
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+)
+
+// MessageCatalog maps every error category to the message shown to the user.
+type MessageCatalog map[parsers.ErrorCategory]string
+
+var DefaultMessages = MessageCatalog{
+	parsers.ErrorCategoryUnknownAlienWord:  "Requested number contains unknown words",
+	parsers.ErrorCategoryInvalidRoman:      "Requested number is in invalid format",
+	parsers.ErrorCategoryOutOfRange:        "Requested number is out of range",
+	parsers.ErrorCategoryUnknownCommodity:  "Requested commodity is unknown",
+	parsers.ErrorCategoryNoAlienWord:       "Requested number cannot be said in alien words",
+	parsers.ErrorCategoryUnknownCurrency:   "Requested currency is unknown",
+	parsers.ErrorCategoryNoExchangeRate:    "Requested currencies cannot be exchanged",
+	parsers.ErrorCategoryUnknownDialect:    "Requested dialect is unknown",
+	parsers.ErrorCategoryMixedDialects:     "Requested number mixes dialects",
+	parsers.ErrorCategoryNoPrice:           "Requested commodity has no price at that date",
+	parsers.ErrorCategoryMalformedQuestion: unknownAnswer,
+	parsers.ErrorCategoryUnknown:           unknownAnswer,
+}
+
+// LoadMessageCatalog reads a JSON object of category to message, categories
+// missing from it keep their default message.
+func LoadMessageCatalog(r io.Reader) (MessageCatalog, error) {
+	messages := MessageCatalog{}
+	err := json.NewDecoder(r).Decode(&messages)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// Message returns the message of category, falling back to the default catalog.
+func (m MessageCatalog) Message(category parsers.ErrorCategory) string {
+	if message, ok := m[category]; ok {
+		return message
+	}
+
+	if message, ok := DefaultMessages[category]; ok {
+		return message
+	}
+
+	return unknownAnswer
+}
@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/pkg/renderers/renderer.go
+
+// Package mock_renderers is a generated GoMock package.
+package mock_renderers
+
+import (
+	reflect "reflect"
+
+	parsers "github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRendererService is a mock of RendererService interface.
+type MockRendererService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRendererServiceMockRecorder
+}
+
+// MockRendererServiceMockRecorder is the mock recorder for MockRendererService.
+type MockRendererServiceMockRecorder struct {
+	mock *MockRendererService
+}
+
+// NewMockRendererService creates a new mock instance.
+func NewMockRendererService(ctrl *gomock.Controller) *MockRendererService {
+	mock := &MockRendererService{ctrl: ctrl}
+	mock.recorder = &MockRendererServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRendererService) EXPECT() *MockRendererServiceMockRecorder {
+	return m.recorder
+}
+
+// Render mocks base method.
+func (m *MockRendererService) Render(answer parsers.Answer) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Render", answer)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Render indicates an expected call of Render.
+func (mr *MockRendererServiceMockRecorder) Render(answer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockRendererService)(nil).Render), answer)
+}
@@ -0,0 +1,131 @@
+package renderers
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+)
+
+const unknownAnswer = "I have no idea what you are talking about"
+
+type RendererService interface {
+	Render(answer parsers.Answer) string
+}
+
+type text struct {
+	title         cases.Caser
+	messages      MessageCatalog
+	rounding      rationals.Rounding
+	alienNumerals bool
+}
+
+var _ RendererService = (*text)(nil)
+
+type NewTextParams struct {
+	// Messages overrides the default message of some error categories.
+	Messages MessageCatalog
+	// Rounding writes the credits, rationals.DefaultRounding when its mode is not set.
+	Rounding rationals.Rounding
+	// AlienNumerals writes the whole amounts of an exchange with alien words.
+	AlienNumerals bool
+}
+
+// NewText creates a renderer producing the human sentences of the guide.
+func NewText(p NewTextParams) *text {
+	rounding := p.Rounding
+	if rounding.Mode == "" {
+		rounding = rationals.DefaultRounding
+	}
+
+	return &text{
+		title:         cases.Title(language.AmericanEnglish, cases.Compact),
+		messages:      p.Messages,
+		rounding:      rounding,
+		alienNumerals: p.AlienNumerals,
+	}
+}
+
+func (t *text) Render(answer parsers.Answer) string {
+	if answer.Err != nil {
+		return t.messages.Message(answer.Category)
+	}
+
+	if answer.AsOf != "" {
+		return t.sentence(answer) + " as of " + answer.AsOf
+	}
+
+	return t.sentence(answer)
+}
+
+func (t *text) sentence(answer parsers.Answer) string {
+	switch answer.Kind {
+	case parsers.QuestionKindHowMuch:
+		return alien(answer.Operands[0]) + " is " + strconv.Itoa(answer.Operands[0].Value)
+	case parsers.QuestionKindArithmetic:
+		value := answer.Expression + " is " + answer.Value.String()
+		if len(answer.Alien) == 0 {
+			return value
+		}
+
+		// the result is written in the dialect of the operands
+		return value + " or " + alien(parsers.Operand{Alien: answer.Alien, Dialect: answer.Operands[0].Dialect})
+	case parsers.QuestionKindHowMany:
+		currency := "Credits"
+		if answer.Currency != "" {
+			currency = answer.Currency
+		}
+
+		return alien(answer.Operands[0]) + " " + answer.Commodity + " is " + t.rounding.Format(answer.Value) + " " + currency
+	case parsers.QuestionKindDoes:
+		operand1 := alien(answer.Operands[0]) + " " + t.title.String(answer.Operands[0].Commodity)
+		operand2 := alien(answer.Operands[1]) + " " + t.title.String(answer.Operands[1].Commodity)
+
+		switch answer.Comparison {
+		case parsers.ComparisonLess:
+			return operand1 + " has less Credits than " + operand2
+		case parsers.ComparisonMore:
+			return operand1 + " has more Credits than " + operand2
+		default:
+			return operand1 + " has equal Credits to " + operand2
+		}
+	case parsers.QuestionKindIs:
+		operand1 := alien(answer.Operands[0])
+		operand2 := alien(answer.Operands[1])
+
+		switch answer.Comparison {
+		case parsers.ComparisonLess:
+			return operand1 + " is smaller than " + operand2
+		case parsers.ComparisonMore:
+			return operand1 + " is larger than " + operand2
+		default:
+			return operand1 + " is equal to " + operand2
+		}
+	case parsers.QuestionKindSay:
+		return strconv.Itoa(answer.Operands[0].Value) + " is " + strings.Join(answer.Alien, " ")
+	case parsers.QuestionKindHowManyFor:
+		return t.rounding.Format(answer.Value) + " Credits buys " + strings.Join(answer.Alien, " ") + " " + answer.Commodity
+	case parsers.QuestionKindExchange:
+		amount := t.rounding.Format(answer.Value)
+		if t.alienNumerals && len(answer.Alien) > 0 {
+			amount = strings.Join(answer.Alien, " ")
+		}
+
+		return alien(answer.Operands[0]) + " " + answer.Operands[0].Commodity + " is " + amount + " " + answer.Commodity
+	default:
+		return t.messages.Message(parsers.ErrorCategoryUnknown)
+	}
+}
+
+// alien writes the alien words of operand, qualified by their dialect unless it is the default one.
+func alien(operand parsers.Operand) string {
+	if operand.Dialect != "" {
+		return operand.Dialect + ": " + strings.Join(operand.Alien, " ")
+	}
+
+	return strings.Join(operand.Alien, " ")
+}
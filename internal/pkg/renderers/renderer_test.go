@@ -0,0 +1,435 @@
+package renderers_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/converters"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+	"github.com/go-test/deep"
+)
+
+func TestTextRender(t *testing.T) {
+
+	renderer := renderers.NewText(renderers.NewTextParams{})
+
+	globGlob := []string{"glob", "glob"}
+	globProk := []string{"glob", "prok"}
+
+	type args struct {
+		param parsers.Answer
+	}
+
+	type want struct {
+		result string
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when how much is answered should render the value",
+			args: args{
+				param: parsers.Answer{
+					Kind:     parsers.QuestionKindHowMuch,
+					Operands: []parsers.Operand{{Alien: globProk, Value: 4}},
+					Value:    rationals.FromInt(4),
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob prok is 4",
+			},
+		},
+		{
+			name: "when how much is answered in a dialect should qualify the alien words",
+			args: args{
+				param: parsers.Answer{
+					Kind:     parsers.QuestionKindHowMuch,
+					Operands: []parsers.Operand{{Alien: globProk, Value: 4, Dialect: "vega"}},
+					Value:    rationals.FromInt(4),
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "vega: glob prok is 4",
+			},
+		},
+		{
+			name: "when arithmetic is answered should render the value and the alien words",
+			args: args{
+				param: parsers.Answer{
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: globGlob, Value: 2}, {Alien: globProk, Value: 4}},
+					Value:      rationals.FromInt(6),
+					Expression: "glob glob plus glob prok",
+					Alien:      []string{"prok", "glob"},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob glob plus glob prok is 6 or prok glob",
+			},
+		},
+		{
+			name: "when arithmetic is answered in a dialect should qualify the alien words",
+			args: args{
+				param: parsers.Answer{
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: globGlob, Value: 20, Dialect: "vega"}, {Alien: globProk, Value: 15, Dialect: "vega"}},
+					Value:      rationals.FromInt(35),
+					Expression: "vega: glob glob plus vega: glob prok",
+					Alien:      []string{"glob", "glob", "glob", "prok"},
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "vega: glob glob plus vega: glob prok is 35 or vega: glob glob glob prok",
+			},
+		},
+		{
+			name: "when arithmetic result has no alien words should render the value only",
+			args: args{
+				param: parsers.Answer{
+					Kind:       parsers.QuestionKindArithmetic,
+					Operands:   []parsers.Operand{{Alien: globGlob, Value: 2}, {Alien: globProk, Value: 4}},
+					Value:      rationals.FromInt(-2),
+					Expression: "glob glob minus glob prok",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob glob minus glob prok is -2",
+			},
+		},
+		{
+			name: "when how many is answered as of a date should render the date",
+			args: args{
+				param: parsers.Answer{
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: globProk, Value: 4, Commodity: "gold", Credits: rationals.FromInt(57800)}},
+					Value:     rationals.FromInt(57800),
+					Commodity: "gold",
+					AsOf:      "3021-05-01",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob prok gold is 57800 Credits as of 3021-05-01",
+			},
+		},
+		{
+			name: "when how many is answered with decimal should render one decimal",
+			args: args{
+				param: parsers.Answer{
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: globProk, Value: 4, Commodity: "iron", Credits: rationals.New(16031, 2)}},
+					Value:     rationals.New(16031, 2),
+					Commodity: "iron",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob prok iron is 8015.5 Credits",
+			},
+		},
+		{
+			name: "when how many is answered in another currency should render the currency",
+			args: args{
+				param: parsers.Answer{
+					Kind:      parsers.QuestionKindHowMany,
+					Operands:  []parsers.Operand{{Alien: globProk, Value: 4, Commodity: "gold", Credits: rationals.FromInt(57800)}},
+					Value:     rationals.FromInt(173400),
+					Commodity: "gold",
+					Currency:  "zorbs",
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob prok gold is 173400 zorbs",
+			},
+		},
+		{
+			name: "when does is answered should render title cased commodities",
+			args: args{
+				param: parsers.Answer{
+					Kind: parsers.QuestionKindDoes,
+					Operands: []parsers.Operand{
+						{Alien: globGlob, Value: 2, Commodity: "gold"},
+						{Alien: globProk, Value: 4, Commodity: "iron"},
+					},
+					Comparison: parsers.ComparisonEqual,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob glob Gold has equal Credits to glob prok Iron",
+			},
+		},
+		{
+			name: "when is is answered should render the comparison",
+			args: args{
+				param: parsers.Answer{
+					Kind: parsers.QuestionKindIs,
+					Operands: []parsers.Operand{
+						{Alien: globProk, Value: 4},
+						{Alien: globGlob, Value: 2},
+					},
+					Comparison: parsers.ComparisonMore,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob prok is larger than glob glob",
+			},
+		},
+		{
+			name: "when say is answered should render the alien words",
+			args: args{
+				param: parsers.Answer{
+					Kind:     parsers.QuestionKindSay,
+					Operands: []parsers.Operand{{Alien: globProk, Value: 4}},
+					Value:    rationals.FromInt(4),
+					Alien:    globProk,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "4 is glob prok",
+			},
+		},
+		{
+			name: "when how many for is answered should render the amount of commodity",
+			args: args{
+				param: parsers.Answer{
+					Kind:      parsers.QuestionKindHowManyFor,
+					Operands:  []parsers.Operand{{Alien: globGlob, Value: 2, Commodity: "Silver", Credits: rationals.FromInt(34)}},
+					Value:     rationals.FromInt(40),
+					Commodity: "Silver",
+					Alien:     globGlob,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "40 Credits buys glob glob Silver",
+			},
+		},
+		{
+			name: "when answer has invalid roman should render invalid format",
+			args: args{
+				param: parsers.Answer{
+					Kind:     parsers.QuestionKindHowMany,
+					Err:      converters.ErrInvalidRoman,
+					Category: parsers.ErrorCategoryInvalidRoman,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "Requested number is in invalid format",
+			},
+		},
+		{
+			name: "when answer has unknown alien word should render unknown words",
+			args: args{
+				param: parsers.Answer{
+					Kind:     parsers.QuestionKindHowMuch,
+					Err:      converters.ErrUnknownAlienWord,
+					Category: parsers.ErrorCategoryUnknownAlienWord,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "Requested number contains unknown words",
+			},
+		},
+		{
+			name: "when answer has error should render unknown answer",
+			args: args{
+				param: parsers.Answer{
+					Kind:     parsers.QuestionKindHowMuch,
+					Err:      parsers.ErrMalformedQuestion,
+					Category: parsers.ErrorCategoryMalformedQuestion,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "I have no idea what you are talking about",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result := renderer.Render(tc.args.param)
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestTextRenderMessageCatalog(t *testing.T) {
+
+	messages, err := renderers.LoadMessageCatalog(strings.NewReader(`{"unknown_commodity": "Never heard of that commodity"}`))
+	if err != nil {
+		t.Fatalf("got unexpected error: %v", err)
+	}
+
+	renderer := renderers.NewText(renderers.NewTextParams{
+		Messages: messages,
+	})
+
+	type args struct {
+		param parsers.Answer
+	}
+
+	type want struct {
+		result string
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when category is in the catalog should render its message",
+			args: args{
+				param: parsers.Answer{
+					Err:      parsers.ErrUnknownCommodity,
+					Category: parsers.ErrorCategoryUnknownCommodity,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "Never heard of that commodity",
+			},
+		},
+		{
+			name: "when category is not in the catalog should render the default message",
+			args: args{
+				param: parsers.Answer{
+					Err:      converters.ErrOutOfRange,
+					Category: parsers.ErrorCategoryOutOfRange,
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "Requested number is out of range",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result := renderer.Render(tc.args.param)
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestTextRenderExchange(t *testing.T) {
+
+	answer := func(value rationals.Rational, alien []string) parsers.Answer {
+		return parsers.Answer{
+			Kind:      parsers.QuestionKindExchange,
+			Operands:  []parsers.Operand{{Alien: []string{"pish"}, Value: 10, Commodity: "silver", Credits: rationals.FromInt(170)}},
+			Value:     value,
+			Commodity: "iron",
+			Alien:     alien,
+		}
+	}
+
+	type args struct {
+		params renderers.NewTextParams
+		answer parsers.Answer
+	}
+
+	type want struct {
+		result string
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when amount is a fraction should round it",
+			args: args{
+				params: renderers.NewTextParams{},
+				answer: answer(rationals.New(20, 23), nil),
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "pish silver is 0.9 iron",
+			},
+		},
+		{
+			name: "when rounding is exact should render the fraction",
+			args: args{
+				params: renderers.NewTextParams{Rounding: rationals.Rounding{Mode: rationals.RoundExact}},
+				answer: answer(rationals.New(20, 23), nil),
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "pish silver is 20/23 iron",
+			},
+		},
+		{
+			name: "when alien numerals are asked should render the alien words",
+			args: args{
+				params: renderers.NewTextParams{AlienNumerals: true},
+				answer: answer(rationals.FromInt(4), []string{"glob", "prok"}),
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "pish silver is glob prok iron",
+			},
+		},
+		{
+			name: "when amount has no alien words should render the number",
+			args: args{
+				params: renderers.NewTextParams{AlienNumerals: true},
+				answer: answer(rationals.New(20, 23), nil),
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "pish silver is 0.9 iron",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result := renderers.NewText(tc.args.params).Render(tc.args.answer)
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}
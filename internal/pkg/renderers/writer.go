@@ -0,0 +1,235 @@
+package renderers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+)
+
+// Format is the way a run writes its answers.
+type Format string
+
+const (
+	// FormatText writes the sentences of the guide, one per line.
+	FormatText Format = "text"
+	// FormatJSON writes a single array of records.
+	FormatJSON Format = "json"
+	// FormatNDJSON writes a record per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatCSV writes a header followed by a row per record.
+	FormatCSV Format = "csv"
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// AnswerWriter writes the answers of a run, Flush must be called once every answer is written.
+type AnswerWriter interface {
+	Write(answer parsers.Answer) error
+	Flush() error
+}
+
+// Record is the structured form of an answer. Value is the numeric result,
+// Units the amount of commodity, Currency the currency of Value when it is not
+// credits, AsOf the date of the prices when they are not the current ones and
+// ErrorCode the error category.
+type Record struct {
+	File       string      `json:"file,omitempty"`
+	Line       int         `json:"line"`
+	Question   string      `json:"question"`
+	Kind       string      `json:"kind"`
+	Answer     string      `json:"answer"`
+	Value      json.Number `json:"value,omitempty"`
+	Units      *int        `json:"units,omitempty"`
+	Commodity  string      `json:"commodity,omitempty"`
+	Currency   string      `json:"currency,omitempty"`
+	AsOf       string      `json:"as_of,omitempty"`
+	Comparison string      `json:"comparison,omitempty"`
+	ErrorCode  string      `json:"error_code,omitempty"`
+}
+
+var recordHeader = []string{"file", "line", "question", "kind", "answer", "value", "units", "commodity", "currency", "as_of", "comparison", "error_code"}
+
+type NewAnswerWriterParams struct {
+	Format Format
+	Output io.Writer
+	// Renderer writes the sentence of every answer, which is also the answer of the records.
+	Renderer RendererService
+	// Rounding writes the values of the records, rationals.DefaultRounding when its mode is not set.
+	Rounding rationals.Rounding
+}
+
+// ParseFormat returns the format named name.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatText, FormatJSON, FormatNDJSON, FormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w '%s'", ErrUnknownFormat, name)
+	}
+}
+
+// NewAnswerWriter returns the writer of format, the text format when it is not set.
+func NewAnswerWriter(p NewAnswerWriterParams) (AnswerWriter, error) {
+	rounding := p.Rounding
+	if rounding.Mode == "" {
+		rounding = rationals.DefaultRounding
+	}
+
+	records := recorder{renderer: p.Renderer, rounding: rounding}
+
+	switch p.Format {
+	case FormatText, "":
+		return &textWriter{output: p.Output, renderer: p.Renderer}, nil
+	case FormatJSON:
+		return &jsonWriter{output: p.Output, recorder: records, records: []Record{}}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(p.Output), recorder: records}, nil
+	case FormatCSV:
+		return &csvWriter{writer: csv.NewWriter(p.Output), recorder: records}, nil
+	default:
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownFormat, p.Format)
+	}
+}
+
+type recorder struct {
+	renderer RendererService
+	rounding rationals.Rounding
+}
+
+func (r recorder) record(answer parsers.Answer) Record {
+	record := Record{
+		File:       answer.File,
+		Line:       answer.Line,
+		Question:   answer.Question,
+		Kind:       string(answer.Kind),
+		Answer:     r.renderer.Render(answer),
+		Commodity:  answer.Commodity,
+		Currency:   answer.Currency,
+		AsOf:       answer.AsOf,
+		Comparison: string(answer.Comparison),
+		ErrorCode:  string(answer.Category),
+	}
+
+	if answer.Err != nil {
+		return record
+	}
+
+	switch answer.Kind {
+	case parsers.QuestionKindHowMuch, parsers.QuestionKindArithmetic, parsers.QuestionKindHowMany, parsers.QuestionKindSay, parsers.QuestionKindHowManyFor, parsers.QuestionKindExchange:
+		// a JSON number cannot be a fraction
+		record.Value = json.Number(r.rounding.Decimal().Format(answer.Value))
+	}
+
+	switch answer.Kind {
+	case parsers.QuestionKindHowMany, parsers.QuestionKindHowManyFor:
+		units := answer.Operands[0].Value
+		record.Units = &units
+	}
+
+	return record
+}
+
+type textWriter struct {
+	output   io.Writer
+	renderer RendererService
+}
+
+func (t *textWriter) Write(answer parsers.Answer) error {
+	_, err := fmt.Fprintln(t.output, t.renderer.Render(answer))
+
+	return err
+}
+
+func (t *textWriter) Flush() error {
+	return nil
+}
+
+type jsonWriter struct {
+	output io.Writer
+	recorder
+	records []Record
+}
+
+// Write keeps the record until Flush writes the whole array.
+func (j *jsonWriter) Write(answer parsers.Answer) error {
+	j.records = append(j.records, j.record(answer))
+
+	return nil
+}
+
+func (j *jsonWriter) Flush() error {
+	encoder := json.NewEncoder(j.output)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(j.records)
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+	recorder
+}
+
+func (n *ndjsonWriter) Write(answer parsers.Answer) error {
+	return n.encoder.Encode(n.record(answer))
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	recorder
+	wroteHeader bool
+}
+
+func (c *csvWriter) Write(answer parsers.Answer) error {
+	if !c.wroteHeader {
+		if err := c.writer.Write(recordHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	record := c.record(answer)
+
+	units := ""
+	if record.Units != nil {
+		units = strconv.Itoa(*record.Units)
+	}
+
+	return c.writer.Write([]string{
+		record.File,
+		strconv.Itoa(record.Line),
+		record.Question,
+		record.Kind,
+		record.Answer,
+		record.Value.String(),
+		units,
+		record.Commodity,
+		record.Currency,
+		record.AsOf,
+		record.Comparison,
+		record.ErrorCode,
+	})
+}
+
+// Flush writes the header even when there was no answer.
+func (c *csvWriter) Flush() error {
+	if !c.wroteHeader {
+		if err := c.writer.Write(recordHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	c.writer.Flush()
+
+	return c.writer.Error()
+}
@@ -0,0 +1,187 @@
+package renderers_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/parsers"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/renderers"
+	"github.com/go-test/deep"
+)
+
+func TestAnswerWriter(t *testing.T) {
+
+	renderer := renderers.NewText(renderers.NewTextParams{})
+
+	answers := []parsers.Answer{
+		{
+			File:      "input",
+			Line:      9,
+			Question:  "How many Credits is glob prok Iron ?",
+			Kind:      parsers.QuestionKindHowMany,
+			Operands:  []parsers.Operand{{Alien: []string{"glob", "prok"}, Value: 4, Commodity: "iron", Credits: rationals.New(16031, 2)}},
+			Value:     rationals.New(16031, 2),
+			Commodity: "iron",
+		},
+		{
+			File:     "input",
+			Line:     10,
+			Question: "How much is glob blarg ?",
+			Kind:     parsers.QuestionKindHowMuch,
+			Err:      errors.New("input:10:18: unknown alien word 'blarg'"),
+			Category: parsers.ErrorCategoryUnknownAlienWord,
+		},
+	}
+
+	type args struct {
+		format   renderers.Format
+		rounding rationals.Rounding
+		answers  []parsers.Answer
+	}
+
+	type want struct {
+		result string
+		err    error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when format is text should write the sentences",
+			args: args{
+				format:  renderers.FormatText,
+				answers: answers,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "glob prok iron is 8015.5 Credits\nRequested number contains unknown words\n",
+			},
+		},
+		{
+			name: "when format is json should write an array of records",
+			args: args{
+				format:  renderers.FormatJSON,
+				answers: answers[:1],
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: `[
+  {
+    "file": "input",
+    "line": 9,
+    "question": "How many Credits is glob prok Iron ?",
+    "kind": "how_many",
+    "answer": "glob prok iron is 8015.5 Credits",
+    "value": 8015.5,
+    "units": 4,
+    "commodity": "iron"
+  }
+]
+`,
+			},
+		},
+		{
+			name: "when format is json without answers should write an empty array",
+			args: args{
+				format:  renderers.FormatJSON,
+				answers: []parsers.Answer{},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: "[]\n",
+			},
+		},
+		{
+			name: "when format is ndjson should write a record per line",
+			args: args{
+				format:  renderers.FormatNDJSON,
+				answers: answers,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: `{"file":"input","line":9,"question":"How many Credits is glob prok Iron ?","kind":"how_many","answer":"glob prok iron is 8015.5 Credits","value":8015.5,"units":4,"commodity":"iron"}
+{"file":"input","line":10,"question":"How much is glob blarg ?","kind":"how_much","answer":"Requested number contains unknown words","error_code":"unknown_alien_word"}
+`,
+			},
+		},
+		{
+			name: "when rounding is exact should write the values as decimal numbers",
+			args: args{
+				format:   renderers.FormatNDJSON,
+				rounding: rationals.Rounding{Mode: rationals.RoundExact, Precision: 0},
+				answers:  answers[:1],
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: `{"file":"input","line":9,"question":"How many Credits is glob prok Iron ?","kind":"how_many","answer":"glob prok iron is 8015.5 Credits","value":8016,"units":4,"commodity":"iron"}
+`,
+			},
+		},
+		{
+			name: "when format is csv should write a header and a row per record",
+			args: args{
+				format:  renderers.FormatCSV,
+				answers: answers,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: `file,line,question,kind,answer,value,units,commodity,currency,as_of,comparison,error_code
+input,9,How many Credits is glob prok Iron ?,how_many,glob prok iron is 8015.5 Credits,8015.5,4,iron,,,,
+input,10,How much is glob blarg ?,how_much,Requested number contains unknown words,,,,,,,unknown_alien_word
+`,
+			},
+		},
+		{
+			name: "when format is unknown should return error",
+			args: args{
+				format: renderers.Format("yaml"),
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				err: errors.New("unknown format 'yaml'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.beforeEach(t, &tc.args)
+
+			output := &bytes.Buffer{}
+			writer, err := renderers.NewAnswerWriter(renderers.NewAnswerWriterParams{
+				Format:   tc.args.format,
+				Output:   output,
+				Renderer: renderer,
+				Rounding: tc.args.rounding,
+			})
+			if err != nil {
+				if diff := deep.Equal(err.Error(), tc.want.err.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.err, err, diff)
+				}
+				return
+			}
+			if tc.want.err != nil {
+				t.Fatalf("expected error %v", tc.want.err)
+			}
+
+			for _, answer := range tc.args.answers {
+				if err := writer.Write(answer); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := deep.Equal(output.String(), tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, output.String(), diff)
+			}
+		})
+	}
+}
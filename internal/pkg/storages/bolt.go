@@ -0,0 +1,262 @@
+package storages
+
+import (
+	"strings"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+)
+
+const (
+	// openTimeout bounds the wait for the lock held by another process on the database.
+	openTimeout = time.Second
+	// rateKeySeparator joins the currencies of a rate into its key, a currency never has a space.
+	rateKeySeparator = " "
+)
+
+var (
+	dictionaryBucket = []byte("dictionary")
+	pricesBucket     = []byte("prices")
+	ratesBucket      = []byte("rates")
+	dialectsBucket   = []byte("dialects")
+	historyBucket    = []byte("history")
+	settingsBucket   = []byte("settings")
+	numeralsKey      = []byte("numerals")
+)
+
+type bolt struct {
+	db *bbolt.DB
+}
+
+var _ StorageService = (*bolt)(nil)
+
+type NewBoltParams struct {
+	// Path is the database file, it is created when missing.
+	Path string
+}
+
+// NewBolt returns a storage keeping the knowledge base in an embedded bbolt
+// database, one bucket per kind of knowledge and one nested bucket per dialect
+// and per commodity with a price history.
+func NewBolt(p NewBoltParams) (*bolt, error) {
+	db, err := bbolt.Open(p.Path, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	return &bolt{
+		db: db,
+	}, nil
+}
+
+func (b *bolt) Load() (KnowledgeBase, error) {
+	knowledge := NewKnowledgeBase()
+
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		if settings := tx.Bucket(settingsBucket); settings != nil {
+			knowledge.Numerals = string(settings.Get(numeralsKey))
+		}
+
+		if dictionary := tx.Bucket(dictionaryBucket); dictionary != nil {
+			err := dictionary.ForEach(func(alien, symbol []byte) error {
+				knowledge.Dictionary[string(alien)] = string(symbol)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if prices := tx.Bucket(pricesBucket); prices != nil {
+			err := prices.ForEach(func(commodity, price []byte) error {
+				var value rationals.Rational
+				if err := value.UnmarshalText(price); err != nil {
+					return err
+				}
+
+				knowledge.Prices[string(commodity)] = value
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// the keys are sorted, so are the rates
+		if rates := tx.Bucket(ratesBucket); rates != nil {
+			err := rates.ForEach(func(currencies, rate []byte) error {
+				from, to, _ := strings.Cut(string(currencies), rateKeySeparator)
+
+				var value rationals.Rational
+				if err := value.UnmarshalText(rate); err != nil {
+					return err
+				}
+
+				knowledge.Rates = append(knowledge.Rates, Rate{From: from, To: to, Rate: value})
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if history := tx.Bucket(historyBucket); history != nil {
+			err := history.ForEachBucket(func(commodity []byte) error {
+				if knowledge.History == nil {
+					knowledge.History = map[string]map[string]rationals.Rational{}
+				}
+
+				prices := map[string]rationals.Rational{}
+				knowledge.History[string(commodity)] = prices
+
+				return history.Bucket(commodity).ForEach(func(date, price []byte) error {
+					var value rationals.Rational
+					if err := value.UnmarshalText(price); err != nil {
+						return err
+					}
+
+					prices[string(date)] = value
+					return nil
+				})
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if dialects := tx.Bucket(dialectsBucket); dialects != nil {
+			return dialects.ForEachBucket(func(dialect []byte) error {
+				if knowledge.Dialects == nil {
+					knowledge.Dialects = map[string]map[string]string{}
+				}
+
+				dictionary := map[string]string{}
+				knowledge.Dialects[string(dialect)] = dictionary
+
+				return dialects.Bucket(dialect).ForEach(func(alien, symbol []byte) error {
+					dictionary[string(alien)] = string(symbol)
+					return nil
+				})
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return KnowledgeBase{}, err
+	}
+
+	return knowledge, nil
+}
+
+// Save replaces every bucket in a single transaction.
+func (b *bolt) Save(knowledge KnowledgeBase) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{settingsBucket, dictionaryBucket, pricesBucket, ratesBucket, dialectsBucket, historyBucket} {
+			if tx.Bucket(name) == nil {
+				continue
+			}
+
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+
+		settings, err := tx.CreateBucket(settingsBucket)
+		if err != nil {
+			return err
+		}
+		if knowledge.Numerals != "" {
+			if err := settings.Put(numeralsKey, []byte(knowledge.Numerals)); err != nil {
+				return err
+			}
+		}
+
+		dictionary, err := tx.CreateBucket(dictionaryBucket)
+		if err != nil {
+			return err
+		}
+		for alien, symbol := range knowledge.Dictionary {
+			if err := dictionary.Put([]byte(alien), []byte(symbol)); err != nil {
+				return err
+			}
+		}
+
+		prices, err := tx.CreateBucket(pricesBucket)
+		if err != nil {
+			return err
+		}
+		for commodity, price := range knowledge.Prices {
+			text, err := price.MarshalText()
+			if err != nil {
+				return err
+			}
+
+			if err := prices.Put([]byte(commodity), text); err != nil {
+				return err
+			}
+		}
+
+		rates, err := tx.CreateBucket(ratesBucket)
+		if err != nil {
+			return err
+		}
+		for _, rate := range knowledge.Rates {
+			text, err := rate.Rate.MarshalText()
+			if err != nil {
+				return err
+			}
+
+			if err := rates.Put([]byte(rate.From+rateKeySeparator+rate.To), text); err != nil {
+				return err
+			}
+		}
+
+		history, err := tx.CreateBucket(historyBucket)
+		if err != nil {
+			return err
+		}
+		for commodity, dated := range knowledge.History {
+			prices, err := history.CreateBucket([]byte(commodity))
+			if err != nil {
+				return err
+			}
+
+			for date, price := range dated {
+				text, err := price.MarshalText()
+				if err != nil {
+					return err
+				}
+
+				if err := prices.Put([]byte(date), text); err != nil {
+					return err
+				}
+			}
+		}
+
+		dialects, err := tx.CreateBucket(dialectsBucket)
+		if err != nil {
+			return err
+		}
+		for dialect, words := range knowledge.Dialects {
+			dictionary, err := dialects.CreateBucket([]byte(dialect))
+			if err != nil {
+				return err
+			}
+
+			for alien, symbol := range words {
+				if err := dictionary.Put([]byte(alien), []byte(symbol)); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (b *bolt) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,63 @@
+package storages
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type jsonFile struct {
+	path string
+}
+
+var _ StorageService = (*jsonFile)(nil)
+
+type NewJSONFileParams struct {
+	// Path is the file holding the knowledge base, it is created by the first Save.
+	Path string
+}
+
+// NewJSONFile returns a storage keeping the knowledge base in a JSON file.
+func NewJSONFile(p NewJSONFileParams) *jsonFile {
+	return &jsonFile{
+		path: p.Path,
+	}
+}
+
+func (j *jsonFile) Load() (KnowledgeBase, error) {
+	file, err := os.Open(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewKnowledgeBase(), nil
+	}
+	if err != nil {
+		return KnowledgeBase{}, err
+	}
+	defer file.Close()
+
+	return Decode(file)
+}
+
+// Save writes knowledge to a temporary file renamed over the previous one, so
+// that an interrupted save never leaves a truncated knowledge base behind.
+func (j *jsonFile) Save(knowledge KnowledgeBase) error {
+	file, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	err = Encode(file, knowledge)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), j.path)
+}
+
+func (j *jsonFile) Close() error {
+	return nil
+}
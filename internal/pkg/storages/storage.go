@@ -0,0 +1,79 @@
+package storages
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+)
+
+// KnowledgeBase is everything the guide learned from the definitions.
+type KnowledgeBase struct {
+	// Numerals is the declared numeral system, the default one when empty.
+	Numerals   string            `json:"numerals,omitempty"`
+	Dictionary map[string]string `json:"dictionary"`
+	// Prices are the prices without date.
+	Prices map[string]rationals.Rational `json:"prices"`
+	// History are the prices of every commodity by the date they are effective from.
+	History map[string]map[string]rationals.Rational `json:"history,omitempty"`
+	// Rates are the exchange rates between currencies, sorted by currencies.
+	Rates []Rate `json:"rates,omitempty"`
+	// Dialects are the alien words of every dialect but the default one, the Dictionary.
+	Dialects map[string]map[string]string `json:"dialects,omitempty"`
+}
+
+// Rate tells that a unit of From is worth Rate units of To.
+type Rate struct {
+	From string             `json:"from"`
+	To   string             `json:"to"`
+	Rate rationals.Rational `json:"rate"`
+}
+
+// StorageService keeps the knowledge base between runs.
+type StorageService interface {
+	// Load returns the saved knowledge base, an empty one when nothing was saved yet.
+	Load() (KnowledgeBase, error)
+	// Save replaces the saved knowledge base by knowledge.
+	Save(knowledge KnowledgeBase) error
+	Close() error
+}
+
+var ErrInvalidKnowledgeBase = errors.New("invalid knowledge base")
+
+// NewKnowledgeBase returns an empty knowledge base.
+func NewKnowledgeBase() KnowledgeBase {
+	return KnowledgeBase{
+		Dictionary: map[string]string{},
+		Prices:     map[string]rationals.Rational{},
+	}
+}
+
+// Encode writes knowledge as JSON, the format of the exported knowledge bases.
+func Encode(w io.Writer, knowledge KnowledgeBase) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(knowledge)
+}
+
+// Decode reads a knowledge base written by Encode.
+func Decode(r io.Reader) (KnowledgeBase, error) {
+	knowledge := NewKnowledgeBase()
+
+	err := json.NewDecoder(r).Decode(&knowledge)
+	if err != nil {
+		return KnowledgeBase{}, fmt.Errorf("%w: %s", ErrInvalidKnowledgeBase, err)
+	}
+
+	// a null object leaves the maps nil
+	if knowledge.Dictionary == nil {
+		knowledge.Dictionary = map[string]string{}
+	}
+	if knowledge.Prices == nil {
+		knowledge.Prices = map[string]rationals.Rational{}
+	}
+
+	return knowledge, nil
+}
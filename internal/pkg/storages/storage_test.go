@@ -0,0 +1,190 @@
+package storages_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arieffian/roman-alien-currency/internal/pkg/rationals"
+	"github.com/arieffian/roman-alien-currency/internal/pkg/storages"
+	"github.com/go-test/deep"
+)
+
+func TestStorages(t *testing.T) {
+
+	knowledge := storages.KnowledgeBase{
+		Numerals:   "mayan",
+		Dictionary: map[string]string{"glob": "𝋡", "prok": "𝋥"},
+		Prices:     map[string]rationals.Rational{"gold": rationals.New(57800, 6), "dirt": rationals.New(1, 10)},
+		History: map[string]map[string]rationals.Rational{
+			"gold": {"3021-05-01": rationals.FromInt(14450), "3021-06-01": rationals.New(29801, 2)},
+		},
+		Rates: []storages.Rate{
+			{From: "credits", To: "zorbs", Rate: rationals.FromInt(3)},
+			{From: "zorbs", To: "blips", Rate: rationals.New(1, 2)},
+		},
+		Dialects: map[string]map[string]string{
+			"vega":  {"glob": "𝋢", "blip": "𝋡"},
+			"orion": {"zib": "𝋣"},
+		},
+	}
+
+	type args struct {
+		open func(t *testing.T, path string) storages.StorageService
+	}
+
+	type want struct {
+		empty storages.KnowledgeBase
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when storage is a json file should load what was saved",
+			args: args{
+				open: func(t *testing.T, path string) storages.StorageService {
+					return storages.NewJSONFile(storages.NewJSONFileParams{Path: path})
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				empty: storages.NewKnowledgeBase(),
+			},
+		},
+		{
+			name: "when storage is a bolt database should load what was saved",
+			args: args{
+				open: func(t *testing.T, path string) storages.StorageService {
+					storage, err := storages.NewBolt(storages.NewBoltParams{Path: path})
+					if err != nil {
+						t.Fatalf("got unexpected error: %v", err)
+					}
+
+					return storage
+				},
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				empty: storages.NewKnowledgeBase(),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			path := filepath.Join(t.TempDir(), "knowledge")
+
+			storage := tc.args.open(t, path)
+			empty, err := storage.Load()
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			if diff := deep.Equal(empty, tc.want.empty); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.empty, empty, diff)
+			}
+
+			if err := storage.Save(knowledge); err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+			if err := storage.Close(); err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			// a new storage reads what the previous run saved
+			storage = tc.args.open(t, path)
+			defer storage.Close()
+
+			result, err := storage.Load()
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			if diff := deep.Equal(result, knowledge); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", knowledge, result, diff)
+			}
+		})
+
+	}
+}
+
+func TestDecode(t *testing.T) {
+
+	type args struct {
+		param string
+	}
+
+	type want struct {
+		result storages.KnowledgeBase
+		error  error
+	}
+
+	testcases := []struct {
+		name       string
+		args       args
+		beforeEach func(*testing.T, *args)
+		want       want
+	}{
+		{
+			name: "when knowledge base is valid should return it",
+			args: args{
+				param: `{"dictionary": {"glob": "i"}, "prices": {"gold": "28900/3", "iron": "195.5"}}`,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.KnowledgeBase{
+					Dictionary: map[string]string{"glob": "i"},
+					Prices:     map[string]rationals.Rational{"gold": rationals.New(28900, 3), "iron": rationals.New(391, 2)},
+				},
+			},
+		},
+		{
+			name: "when knowledge base is null should return an empty one",
+			args: args{
+				param: `{"dictionary": null}`,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				result: storages.NewKnowledgeBase(),
+			},
+		},
+		{
+			name: "when price is invalid should return error",
+			args: args{
+				param: `{"prices": {"gold": "a lot"}}`,
+			},
+			beforeEach: func(t *testing.T, a *args) {},
+			want: want{
+				error: errors.New("invalid knowledge base: invalid number 'a lot'"),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tc.beforeEach(t, &tc.args)
+
+			result, err := storages.Decode(strings.NewReader(tc.args.param))
+
+			if err != nil || tc.want.error != nil {
+				if diff := deep.Equal(err.Error(), tc.want.error.Error()); diff != nil {
+					t.Errorf("got unexpected error.\n expect: %v\n actual: %v\n diff: %v\n", tc.want.error, err, diff)
+				}
+			}
+
+			if diff := deep.Equal(result, tc.want.result); diff != nil {
+				t.Errorf("got unexpected result.\n expected: %v\n actual: %v\n diff: %v\n", tc.want.result, result, diff)
+			}
+		})
+
+	}
+}